@@ -0,0 +1,132 @@
+package timewindow
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ParseAbsoluteWindow parses a one-off window from RFC 3339 timestamps (for
+// example "2026-11-03T22:00:00Z").
+func ParseAbsoluteWindow(start, end string) (*AbsoluteWindow, error) {
+	s, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return nil, fmt.Errorf("start: %w", err)
+	}
+
+	e, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		return nil, fmt.Errorf("end: %w", err)
+	}
+
+	if e.Before(s) {
+		return nil, fmt.Errorf("end %v is before start %v", end, start)
+	}
+
+	return &AbsoluteWindow{Start: s, End: e}, nil
+}
+
+// absoluteWindowJSON is the JSON and YAML representation of an
+// AbsoluteWindow. The times are written in RFC 3339, which keeps their offset
+// from UTC but not their location.
+type absoluteWindowJSON struct {
+	Start string `json:"start" yaml:"start"`
+	End   string `json:"end" yaml:"end"`
+}
+
+func newAbsoluteWindowJSON(w AbsoluteWindow) (absoluteWindowJSON, error) {
+	return absoluteWindowJSON{Start: w.Start.Format(time.RFC3339Nano), End: w.End.Format(time.RFC3339Nano)}, nil
+}
+
+// window parses v with the same validation as ParseAbsoluteWindow.
+func (v absoluteWindowJSON) window() (*AbsoluteWindow, error) {
+	return ParseAbsoluteWindow(v.Start, v.End)
+}
+
+// MarshalJSON implements json.Marshaler. The window is written as an object
+// such as {"start":"2026-11-03T22:00:00Z","end":"2026-11-04T02:00:00Z"}.
+func (w AbsoluteWindow) MarshalJSON() ([]byte, error) {
+	v, err := newAbsoluteWindowJSON(w)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The fields are validated like
+// the arguments of ParseAbsoluteWindow.
+func (w *AbsoluteWindow) UnmarshalJSON(b []byte) error {
+	var v absoluteWindowJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	return w.set(v)
+}
+
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2 and
+// gopkg.in/yaml.v3.
+func (w AbsoluteWindow) MarshalYAML() (interface{}, error) {
+	return newAbsoluteWindowJSON(w)
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2,
+// which gopkg.in/yaml.v3 also supports.
+func (w *AbsoluteWindow) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v absoluteWindowJSON
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	return w.set(v)
+}
+
+// set sets w to the window parsed from v.
+func (w *AbsoluteWindow) set(v absoluteWindowJSON) error {
+	parsed, err := v.window()
+	if err != nil {
+		return err
+	}
+	*w = *parsed
+	return nil
+}
+
+// AbsoluteWindow is a window that occurs exactly once, such as a one-off
+// maintenance window.
+type AbsoluteWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+var _ Window = &AbsoluteWindow{}
+
+// WithinWindow returns true if within the window. It also returns the time until
+// the window starts, which is Never once it has started.
+func (w *AbsoluteWindow) WithinWindow(now time.Time) WindowResult {
+	return withinOccurrences(w, now)
+}
+
+// NextOccurrence returns the window if it starts after t.
+func (w *AbsoluteWindow) NextOccurrence(t time.Time) (Interval, bool) {
+	if w.Start.After(t) {
+		return Interval{Start: w.Start, End: w.End}, true
+	}
+	return Interval{}, false
+}
+
+// PreviousOccurrence returns the window if it starts at or before t.
+func (w *AbsoluteWindow) PreviousOccurrence(t time.Time) (Interval, bool) {
+	if !w.Start.After(t) {
+		return Interval{Start: w.Start, End: w.End}, true
+	}
+	return Interval{}, false
+}
+
+// Occurrences returns the window if it overlaps the range from from (inclusive)
+// to to (exclusive).
+func (w *AbsoluteWindow) Occurrences(from, to time.Time) []Interval {
+	return occurrences(w, from, to)
+}
+
+// Iterate returns an iterator that yields the window if it ends after from.
+func (w *AbsoluteWindow) Iterate(from time.Time) *OccurrenceIterator {
+	return newOccurrenceIterator(w, from)
+}
@@ -0,0 +1,195 @@
+package timewindow
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbsoluteWindow(t *testing.T) {
+	window := AbsoluteWindow{
+		Start: time.Date(2026, time.November, 3, 22, 0, 0, 0, time.UTC),
+		End:   time.Date(2026, time.November, 4, 3, 0, 0, 0, time.UTC),
+	}
+
+	cases := []struct {
+		name string
+		now  time.Time
+
+		result     WindowResult
+		nextOK     bool
+		previousOK bool
+	}{
+		{
+			name: "before",
+			now:  time.Date(2026, time.November, 3, 21, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: time.Hour,
+				TSStart: Never,
+				TSEnd:   Never,
+			},
+			nextOK: true,
+		},
+		{
+			name: "on-start",
+			now:  time.Date(2026, time.November, 3, 22, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 0,
+				TTEnd:   5 * time.Hour,
+				TSStart: 0,
+				TSEnd:   Never,
+			},
+			previousOK: true,
+		},
+		{
+			name: "within",
+			now:  time.Date(2026, time.November, 4, 1, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: Never,
+				TTEnd:   2 * time.Hour,
+				TSStart: 3 * time.Hour,
+				TSEnd:   Never,
+			},
+			previousOK: true,
+		},
+		{
+			name: "on-end",
+			now:  time.Date(2026, time.November, 4, 3, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: Never,
+				TSStart: 5 * time.Hour,
+				TSEnd:   0,
+			},
+			previousOK: true,
+		},
+		{
+			name: "after",
+			now:  time.Date(2026, time.November, 5, 3, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: Never,
+				TSStart: 29 * time.Hour,
+				TSEnd:   24 * time.Hour,
+			},
+			previousOK: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.result, window.WithinWindow(c.now))
+
+			_, ok := window.NextOccurrence(c.now)
+			require.Equal(t, c.nextOK, ok)
+			_, ok = window.PreviousOccurrence(c.now)
+			require.Equal(t, c.previousOK, ok)
+		})
+	}
+}
+
+func TestAbsoluteWindowCombined(t *testing.T) {
+	oneOff := &AbsoluteWindow{
+		Start: time.Date(2026, time.November, 3, 22, 0, 0, 0, time.UTC),
+		End:   time.Date(2026, time.November, 4, 3, 0, 0, 0, time.UTC),
+	}
+	nightly := &TODWindow{Start: TOD{Hour: 2}, End: TOD{Hour: 4}}
+
+	result := Union(nightly, oneOff).WithinWindow(time.Date(2026, time.November, 3, 23, 0, 0, 0, time.UTC))
+	require.True(t, result.Within)
+	require.Equal(t, 5*time.Hour, result.TTEnd)
+
+	result = Difference(nightly, oneOff).WithinWindow(time.Date(2026, time.November, 4, 1, 0, 0, 0, time.UTC))
+	require.False(t, result.Within)
+	require.Equal(t, 2*time.Hour, result.TTStart)
+}
+
+func TestParseAbsoluteWindowHappyPath(t *testing.T) {
+	w, err := ParseAbsoluteWindow("2026-11-03T22:00:00Z", "2026-11-04T03:00:00+01:00")
+	require.NoError(t, err)
+	require.True(t, time.Date(2026, time.November, 3, 22, 0, 0, 0, time.UTC).Equal(w.Start))
+	require.True(t, time.Date(2026, time.November, 4, 2, 0, 0, 0, time.UTC).Equal(w.End))
+}
+
+func TestParseAbsoluteWindowSadPath(t *testing.T) {
+	cases := []struct {
+		name    string
+		start   string
+		end     string
+		errText string
+	}{
+		{
+			name:    "invalid-start",
+			start:   "2026-11-03 22:00",
+			end:     "2026-11-04T03:00:00Z",
+			errText: "start",
+		},
+		{
+			name:    "invalid-end",
+			start:   "2026-11-03T22:00:00Z",
+			end:     "tomorrow",
+			errText: "end",
+		},
+		{
+			name:    "end-before-start",
+			start:   "2026-11-04T03:00:00Z",
+			end:     "2026-11-03T22:00:00Z",
+			errText: "before start",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ParseAbsoluteWindow(c.start, c.end)
+			require.Contains(t, err.Error(), c.errText)
+		})
+	}
+}
+
+func TestAbsoluteWindowJSON(t *testing.T) {
+	w := AbsoluteWindow{
+		Start: time.Date(2026, time.November, 3, 22, 0, 0, 0, time.UTC),
+		End:   time.Date(2026, time.November, 4, 2, 0, 0, 500, time.UTC),
+	}
+
+	b, err := json.Marshal(w)
+	require.NoError(t, err)
+	require.Equal(t, `{"start":"2026-11-03T22:00:00Z","end":"2026-11-04T02:00:00.0000005Z"}`, string(b))
+
+	var parsed AbsoluteWindow
+	require.NoError(t, json.Unmarshal(b, &parsed))
+	require.Equal(t, w, parsed)
+}
+
+func TestAbsoluteWindowJSONSadPath(t *testing.T) {
+	cases := []struct {
+		json string
+		err  string
+	}{
+		{
+			json: `{"start":"2026-11-03T22:00:00Z"}`,
+			err:  `end: parsing time "" as "2006-01-02T15:04:05Z07:00": cannot parse "" as "2006"`,
+		},
+		{
+			json: `{"start":"2026-11-04T02:00:00Z","end":"2026-11-03T22:00:00Z"}`,
+			err:  "end 2026-11-03T22:00:00Z is before start 2026-11-04T02:00:00Z",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.json, func(t *testing.T) {
+			var w AbsoluteWindow
+			require.EqualError(t, json.Unmarshal([]byte(c.json), &w), c.err)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package timewindow_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nstogner/timewindow"
+)
+
+func ExampleAbsoluteWindow() {
+	window, err := timewindow.ParseAbsoluteWindow("2026-11-03T22:00:00Z", "2026-11-04T03:00:00Z")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	now := time.Date(2026, time.November, 3, 12, 0, 0, 0, time.UTC)
+	result := window.WithinWindow(now)
+	fmt.Println("within: ", result.Within)
+	fmt.Println("untilStart: ", result.TTStart)
+	// Output:
+	// within:  false
+	// untilStart:  10h0m0s
+}
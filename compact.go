@@ -0,0 +1,167 @@
+package timewindow
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseWindow parses one or more windows written in a compact form, such as
+// "Mon-Fri 22:00-02:00 America/Chicago" or "Sat,Sun 00:00-24:00".
+//
+// Each window is an optional list of weekdays, a time range and an optional
+// IANA time zone name. Weekdays are separated by commas, optionally followed
+// or preceded by space, and may be ranges such as "Mon-Fri" or groups such as
+// "weekends" (see ParseWeekdays). A time range
+// is a start and an end such as "22:00-02:00", or a start and a duration such
+// as "22:00+56h". Only windows with weekdays may last for 24h or longer. A
+// window without weekdays is returned as a *TODWindow and a window with
+// weekdays as a *TODWeekWindow.
+//
+// Windows are separated by semicolons or commas. Several windows are returned
+// as a *UnionWindow.
+//
+// The String methods of TODWindow, TODWeekWindow and UnionWindow return the
+// same form, which ParseWindow parses back into an equal window. Weekdays are
+// always returned in TODWeekWindow.Days, so a window whose weekdays were set
+// in the Weekdays map is parsed back into an equivalent window that uses Days
+// instead.
+func ParseWindow(s string) (Window, error) {
+	specs := splitWindowSpecs(s)
+	if len(specs) == 1 {
+		return parseWindowSpec(specs[0])
+	}
+
+	u := &UnionWindow{}
+	for i, spec := range specs {
+		w, err := parseWindowSpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("window %d: %w", i+1, err)
+		}
+		u.Windows = append(u.Windows, w)
+	}
+	return u, nil
+}
+
+// splitWindowSpecs splits s into windows at semicolons and at commas that
+// follow a time range. Other commas separate weekdays.
+func splitWindowSpecs(s string) []string {
+	var specs []string
+	for _, part := range strings.Split(s, ";") {
+		var cur []string
+		for _, piece := range strings.Split(part, ",") {
+			cur = append(cur, piece)
+			if strings.ContainsAny(piece, ":+") {
+				specs = append(specs, strings.Join(cur, ","))
+				cur = nil
+			}
+		}
+		if cur != nil {
+			specs = append(specs, strings.Join(cur, ","))
+		}
+	}
+	return specs
+}
+
+// parseWindowSpec parses a single window.
+func parseWindowSpec(s string) (Window, error) {
+	w, hasWeekdays, err := parseWeekWindowSpec(s)
+	if err != nil {
+		return nil, err
+	}
+	if !hasWeekdays {
+		return &TODWindow{Start: w.Start, End: w.End, Duration: w.Duration, Location: w.Location}, nil
+	}
+	return w, nil
+}
+
+// parseWeekWindowSpec parses a single window and reports whether it has
+// weekdays.
+func parseWeekWindowSpec(s string) (*TODWeekWindow, bool, error) {
+	invalidErr := errors.New("invalid format (expected Mon-Fri 22:00-02:00 America/Chicago): " + strings.TrimSpace(s))
+
+	// Space around the commas of a weekday list, as in "Sat, Sun", is
+	// ignored.
+	parts := strings.Split(s, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	fields := strings.Fields(strings.Join(parts, ","))
+	if len(fields) == 0 {
+		return nil, false, invalidErr
+	}
+
+	w := &TODWeekWindow{}
+	hasWeekdays := !strings.ContainsAny(fields[0][:1], "0123456789")
+	if hasWeekdays {
+		days := strings.Split(fields[0], ",")
+		for _, d := range days {
+			if d == "" {
+				return nil, false, errors.New("weekdays: empty weekday in list: " + fields[0])
+			}
+		}
+		wds, err := ParseWeekdaySet(days)
+		if err != nil {
+			return nil, false, fmt.Errorf("weekdays: %w", err)
+		}
+		w.Days = wds
+		fields = fields[1:]
+	}
+	if len(fields) != 1 && len(fields) != 2 {
+		return nil, false, invalidErr
+	}
+
+	var err error
+	if start, duration, ok := cut(fields[0], "+"); ok {
+		if w.Start, err = parseStartTOD(start); err != nil {
+			return nil, false, fmt.Errorf("start: %w", err)
+		}
+		if w.Duration, err = parseWindowDuration(duration); err != nil {
+			return nil, false, fmt.Errorf("duration: %w", err)
+		}
+		if !hasWeekdays {
+			if err := checkDailyDuration(w.Duration); err != nil {
+				return nil, false, fmt.Errorf("duration: %w", err)
+			}
+		}
+		w.End = w.Start
+	} else if start, end, ok := cut(fields[0], "-"); ok {
+		if w.Start, err = parseStartTOD(start); err != nil {
+			return nil, false, fmt.Errorf("start: %w", err)
+		}
+		if w.End, err = ParseTOD(end); err != nil {
+			return nil, false, fmt.Errorf("end: %w", err)
+		}
+	} else {
+		return nil, false, invalidErr
+	}
+
+	if len(fields) == 2 {
+		if w.Location, err = time.LoadLocation(fields[1]); err != nil {
+			return nil, false, fmt.Errorf("location: %w", err)
+		}
+	}
+
+	return w, hasWeekdays, nil
+}
+
+// formatWindowSpec formats the part of a window that follows the weekdays.
+func formatWindowSpec(start, end TOD, d time.Duration, loc *time.Location) string {
+	s := start.String() + "-" + end.String()
+	if d > 0 {
+		s = start.String() + "+" + d.String()
+	}
+	if loc != nil {
+		s += " " + loc.String()
+	}
+	return s
+}
+
+// cut slices s around the first instance of sep.
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
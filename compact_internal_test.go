@@ -0,0 +1,192 @@
+package timewindow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWindow(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	require.NoError(t, err)
+
+	cases := []struct {
+		name string
+		s    string
+
+		window Window
+		str    string
+	}{
+		{
+			name: "weekday-range",
+			s:    "Mon-Fri 22:00-02:00 America/Chicago",
+			window: &TODWeekWindow{
+				Days:     NewWeekdaySet(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday),
+				Start:    TOD{Hour: 22},
+				End:      TOD{Hour: 2},
+				Location: chicago,
+			},
+			str: "Mon-Fri 22:00-02:00 America/Chicago",
+		},
+		{
+			name:   "weekday-list",
+			s:      "sun,Sat 00:00-24:00",
+			window: NewAllDayWindow(NewWeekdaySet(time.Saturday, time.Sunday)),
+			str:    "Sat,Sun 00:00-24:00",
+		},
+		{
+			name: "wrapping-range",
+			s:    "Fri-Mon,Wed 09:00:30-17:00",
+			window: &TODWeekWindow{
+				Days:  NewWeekdaySet(time.Friday, time.Saturday, time.Sunday, time.Monday, time.Wednesday),
+				Start: TOD{Hour: 9, Second: 30},
+				End:   TOD{Hour: 17},
+			},
+			str: "Mon,Wed,Fri-Sun 09:00:30-17:00",
+		},
+		{
+			name:   "group",
+			s:      "weekends 10:00-16:00",
+			window: &TODWeekWindow{Days: NewWeekdaySet(time.Saturday, time.Sunday), Start: TOD{Hour: 10}, End: TOD{Hour: 16}},
+			str:    "Sat,Sun 10:00-16:00",
+		},
+		{
+			name:   "space-after-comma",
+			s:      "Sat, Sun 00:00-24:00",
+			window: NewAllDayWindow(NewWeekdaySet(time.Saturday, time.Sunday)),
+			str:    "Sat,Sun 00:00-24:00",
+		},
+		{
+			name:   "space-around-comma",
+			s:      "Mon , Wed ,Fri 09:00-17:00",
+			window: &TODWeekWindow{Days: NewWeekdaySet(time.Monday, time.Wednesday, time.Friday), Start: TOD{Hour: 9}, End: TOD{Hour: 17}},
+			str:    "Mon,Wed,Fri 09:00-17:00",
+		},
+		{
+			name:   "no-weekdays",
+			s:      "none 10:00-16:00",
+			window: &TODWeekWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 16}},
+			str:    "none 10:00-16:00",
+		},
+		{
+			name:   "daily",
+			s:      "22:00-02:00",
+			window: &TODWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 2}},
+			str:    "22:00-02:00",
+		},
+		{
+			name:   "duration",
+			s:      "Fri 18:00+60h UTC",
+			window: &TODWeekWindow{Days: NewWeekdaySet(time.Friday), Start: TOD{Hour: 18}, End: TOD{Hour: 18}, Duration: 60 * time.Hour, Location: time.UTC},
+			str:    "Fri 18:00+60h0m0s UTC",
+		},
+		{
+			name: "union",
+			s:    "Mon-Fri 22:00-02:00, Sat, Sun 00:00-24:00; 12:00-13:00",
+			window: Union(
+				&TODWeekWindow{
+					Days:  NewWeekdaySet(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday),
+					Start: TOD{Hour: 22},
+					End:   TOD{Hour: 2},
+				},
+				NewAllDayWindow(NewWeekdaySet(time.Saturday, time.Sunday)),
+				&TODWindow{Start: TOD{Hour: 12}, End: TOD{Hour: 13}},
+			),
+			str: "Mon-Fri 22:00-02:00; Sat,Sun 00:00-24:00; 12:00-13:00",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w, err := ParseWindow(c.s)
+			require.NoError(t, err)
+			require.Equal(t, c.window, w)
+			require.Equal(t, c.str, w.(interface{ String() string }).String())
+
+			// The string form parses back into the same window.
+			w, err = ParseWindow(c.str)
+			require.NoError(t, err)
+			require.Equal(t, c.window, w)
+		})
+	}
+}
+
+func TestParseWindowSadPath(t *testing.T) {
+	cases := []struct {
+		s   string
+		err string
+	}{
+		{
+			s:   "",
+			err: "invalid format (expected Mon-Fri 22:00-02:00 America/Chicago): ",
+		},
+		{
+			s:   "Mon-Fri",
+			err: "invalid format (expected Mon-Fri 22:00-02:00 America/Chicago): Mon-Fri",
+		},
+		{
+			s:   "Mon-Funday 22:00-02:00",
+			err: "weekdays: unrecognized weekday in range mon-funday: funday",
+		},
+		{
+			s:   "Sat, , Sun 00:00-24:00",
+			err: "weekdays: empty weekday in list: Sat,,Sun",
+		},
+		{
+			s:   "Sat, Funday 00:00-24:00",
+			err: "weekdays: unrecognized weekday: Funday",
+		},
+		{
+			s:   "Mon 24:00-02:00",
+			err: "start: 24:00 is only valid as the end of a window",
+		},
+		{
+			s:   "Mon 22:00-25:00",
+			err: "end: invalid hour: 25",
+		},
+		{
+			s:   "Mon 22:00+0h",
+			err: "duration: must be positive",
+		},
+		{
+			s:   "22:00+24h",
+			err: "duration: must be less than 24h",
+		},
+		{
+			s:   "Mon 22:00-02:00 Nowhere/Special",
+			err: "location: unknown time zone Nowhere/Special",
+		},
+		{
+			s:   "Mon 22:00-02:00 UTC extra",
+			err: "invalid format (expected Mon-Fri 22:00-02:00 America/Chicago): Mon 22:00-02:00 UTC extra",
+		},
+		{
+			s:   "Mon 22:00-02:00; Tue 25:00-02:00",
+			err: "window 2: start: invalid hour: 25",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			_, err := ParseWindow(c.s)
+			require.EqualError(t, err, c.err)
+		})
+	}
+}
+
+func TestWindowText(t *testing.T) {
+	var w TODWeekWindow
+	require.NoError(t, w.UnmarshalText([]byte("Mon-Fri 09:00-17:00")))
+	text, err := w.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "Mon-Fri 09:00-17:00", string(text))
+	require.EqualError(t, w.UnmarshalText([]byte("09:00-17:00")), "missing weekdays: 09:00-17:00")
+
+	var d TODWindow
+	require.NoError(t, d.UnmarshalText([]byte("09:00-17:00 UTC")))
+	text, err = d.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "09:00-17:00 UTC", string(text))
+	require.EqualError(t, d.UnmarshalText([]byte("Mon 09:00-17:00")), "unexpected weekdays in daily window: Mon 09:00-17:00")
+}
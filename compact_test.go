@@ -0,0 +1,26 @@
+package timewindow_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nstogner/timewindow"
+)
+
+func ExampleParseWindow() {
+	window, err := timewindow.ParseWindow("Mon-Fri 22:00-02:00 America/Chicago; Sat,Sun 00:00-24:00 America/Chicago")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(window)
+
+	now := time.Date(2021, time.June, 5, 12, 0, 0, 0, time.UTC) // Saturday
+	result := window.WithinWindow(now)
+	fmt.Println("within:", result.Within)
+	fmt.Println("untilEnd:", result.TTEnd)
+	// Output:
+	// Mon-Fri 22:00-02:00 America/Chicago; Sat,Sun 00:00-24:00 America/Chicago
+	// within: true
+	// untilEnd: 41h0m0s
+}
@@ -0,0 +1,299 @@
+package timewindow
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a set of start times described by a cron expression.
+type CronSchedule struct {
+	// expr is the expression that the schedule was parsed from.
+	expr string
+
+	seconds, minutes, hours, daysOfMonth, months, daysOfWeek uint64
+
+	// If both the day of month and the day of week are restricted, a day
+	// matches if either of them matches.
+	restrictedDayOfMonth, restrictedDayOfWeek bool
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonths = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronWeekdays = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// ParseCronSchedule parses a cron expression with 5 fields (minute, hour, day
+// of month, month and day of week) or 6 fields (with a leading second field).
+// Fields can be "*", values, ranges ("1-5") and steps ("*/15", "0-30/10"),
+// separated by commas. Months and days of week can be given by their
+// three-letter English names, and both 0 and 7 are Sunday. The macros @yearly,
+// @annually, @monthly, @weekly, @daily, @midnight and @hourly are also
+// accepted.
+func ParseCronSchedule(expr string) (CronSchedule, error) {
+	parsed := strings.Join(strings.Fields(expr), " ")
+	if m, ok := cronMacros[strings.ToLower(parsed)]; ok {
+		expr = m
+	}
+
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return CronSchedule{}, fmt.Errorf("expected 5 or 6 fields, got %d: %s", len(fields), expr)
+	}
+
+	var (
+		s   CronSchedule
+		err error
+	)
+	if s.seconds, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return CronSchedule{}, fmt.Errorf("second: %w", err)
+	}
+	if s.minutes, err = parseCronField(fields[1], 0, 59, nil); err != nil {
+		return CronSchedule{}, fmt.Errorf("minute: %w", err)
+	}
+	if s.hours, err = parseCronField(fields[2], 0, 23, nil); err != nil {
+		return CronSchedule{}, fmt.Errorf("hour: %w", err)
+	}
+	if s.daysOfMonth, err = parseCronField(fields[3], 1, 31, nil); err != nil {
+		return CronSchedule{}, fmt.Errorf("day of month: %w", err)
+	}
+	if s.months, err = parseCronField(fields[4], 1, 12, cronMonths); err != nil {
+		return CronSchedule{}, fmt.Errorf("month: %w", err)
+	}
+	if s.daysOfWeek, err = parseCronField(fields[5], 0, 7, cronWeekdays); err != nil {
+		return CronSchedule{}, fmt.Errorf("day of week: %w", err)
+	}
+	if s.daysOfWeek&(1<<7) != 0 {
+		s.daysOfWeek = s.daysOfWeek&^(1<<7) | 1
+	}
+
+	s.restrictedDayOfMonth = !strings.HasPrefix(fields[3], "*") && fields[3] != "?"
+	s.restrictedDayOfWeek = !strings.HasPrefix(fields[5], "*") && fields[5] != "?"
+	s.expr = parsed
+
+	return s, nil
+}
+
+// String returns the cron expression that the schedule was parsed from, with
+// its fields separated by single spaces.
+func (s CronSchedule) String() string {
+	return s.expr
+}
+
+// MarshalText implements encoding.TextMarshaler using String. It returns an
+// error for a schedule that was not parsed from an expression.
+func (s CronSchedule) MarshalText() ([]byte, error) {
+	if s.expr == "" {
+		return nil, errors.New("schedule was not parsed from a cron expression")
+	}
+	return []byte(s.expr), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseCronSchedule.
+func (s *CronSchedule) UnmarshalText(text []byte) error {
+	parsed, err := ParseCronSchedule(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// parseCronField parses a cron field into a bit set of the values from min to
+// max that it contains.
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	value := func(s string) (int, error) {
+		if n, ok := names[strings.ToLower(s)]; ok {
+			return n, nil
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("unrecognized value: %s", s)
+		}
+		if n < min || n > max {
+			return 0, fmt.Errorf("value %d out of range %d-%d", n, min, max)
+		}
+		return n, nil
+	}
+
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step: %s", part)
+			}
+			rng, step = part[:i], n
+		}
+
+		var lo, hi int
+		switch bounds := strings.SplitN(rng, "-", 2); {
+		case rng == "*" || rng == "?":
+			lo, hi = min, max
+		case len(bounds) == 2:
+			var err error
+			if lo, err = value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if hi, err = value(bounds[1]); err != nil {
+				return 0, err
+			}
+			if hi < lo {
+				return 0, fmt.Errorf("invalid range: %s", rng)
+			}
+		default:
+			var err error
+			if lo, err = value(rng); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if step > 1 {
+				// "5/15" means every 15 starting at 5.
+				hi = max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// matchesDay returns true if the schedule has start times on the calendar
+// date of day.
+func (s CronSchedule) matchesDay(day time.Time) bool {
+	if s.months&(1<<uint(day.Month())) == 0 {
+		return false
+	}
+
+	dom := s.daysOfMonth&(1<<uint(day.Day())) != 0
+	dow := s.daysOfWeek&(1<<uint(day.Weekday())) != 0
+	if s.restrictedDayOfMonth && s.restrictedDayOfWeek {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// nextSecond returns the first second of the day after the given one that the
+// schedule has a start time at.
+func (s CronSchedule) nextSecond(after int) (int, bool) {
+	h, m, sec := splitSecondOfDay(after + 1)
+	for h < 24 {
+		nh, ok := nextBit(s.hours, h)
+		if !ok || nh > 23 {
+			return 0, false
+		}
+		if nh != h {
+			h, m, sec = nh, 0, 0
+		}
+
+		nm, ok := nextBit(s.minutes, m)
+		if !ok || nm > 59 {
+			h, m, sec = h+1, 0, 0
+			continue
+		}
+		if nm != m {
+			m, sec = nm, 0
+		}
+
+		ns, ok := nextBit(s.seconds, sec)
+		if !ok || ns > 59 {
+			m, sec = m+1, 0
+			if m > 59 {
+				h, m = h+1, 0
+			}
+			continue
+		}
+		return 3600*h + 60*m + ns, true
+	}
+	return 0, false
+}
+
+// previousSecond returns the last second of the day at or before the given
+// one that the schedule has a start time at.
+func (s CronSchedule) previousSecond(atOrBefore int) (int, bool) {
+	h, m, sec := splitSecondOfDay(atOrBefore)
+	for h >= 0 {
+		ph, ok := previousBit(s.hours, h)
+		if !ok {
+			return 0, false
+		}
+		if ph != h {
+			h, m, sec = ph, 59, 59
+		}
+
+		pm, ok := previousBit(s.minutes, m)
+		if !ok {
+			h, m, sec = h-1, 59, 59
+			continue
+		}
+		if pm != m {
+			m, sec = pm, 59
+		}
+
+		ps, ok := previousBit(s.seconds, sec)
+		if !ok {
+			m, sec = m-1, 59
+			if m < 0 {
+				h, m = h-1, 59
+			}
+			continue
+		}
+		return 3600*h + 60*m + ps, true
+	}
+	return 0, false
+}
+
+func splitSecondOfDay(s int) (h, m, sec int) {
+	return s / 3600, s / 60 % 60, s % 60
+}
+
+// nextBit returns the lowest set bit of set that is at or above i.
+func nextBit(set uint64, i int) (int, bool) {
+	if i >= 64 {
+		return 0, false
+	}
+	rest := set >> uint(i) << uint(i)
+	if rest == 0 {
+		return 0, false
+	}
+	return bits.TrailingZeros64(rest), true
+}
+
+// previousBit returns the highest set bit of set that is at or below i.
+func previousBit(set uint64, i int) (int, bool) {
+	if i < 0 {
+		return 0, false
+	}
+	rest := set
+	if i < 63 {
+		rest &= 1<<uint(i+1) - 1
+	}
+	if rest == 0 {
+		return 0, false
+	}
+	return bits.Len64(rest) - 1, true
+}
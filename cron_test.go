@@ -0,0 +1,188 @@
+package timewindow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	cases := []struct {
+		name string
+		expr string
+
+		matches    []time.Time
+		notMatches []time.Time
+		err        string
+	}{
+		{
+			name: "weekdays",
+			expr: "0 2 * * 1-5",
+			matches: []time.Time{
+				time.Date(2021, time.June, 7, 2, 0, 0, 0, time.UTC),
+				time.Date(2021, time.June, 11, 2, 0, 0, 0, time.UTC),
+			},
+			notMatches: []time.Time{
+				time.Date(2021, time.June, 12, 2, 0, 0, 0, time.UTC),
+				time.Date(2021, time.June, 7, 2, 1, 0, 0, time.UTC),
+				time.Date(2021, time.June, 7, 2, 0, 1, 0, time.UTC),
+			},
+		},
+		{
+			name: "seconds-and-steps",
+			expr: "*/20 5/15 0-1 * * *",
+			matches: []time.Time{
+				time.Date(2021, time.June, 7, 0, 5, 40, 0, time.UTC),
+				time.Date(2021, time.June, 7, 1, 50, 0, 0, time.UTC),
+			},
+			notMatches: []time.Time{
+				time.Date(2021, time.June, 7, 0, 0, 0, 0, time.UTC),
+				time.Date(2021, time.June, 7, 2, 5, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "names-and-sunday-as-7",
+			expr: "30 4 * jan,Dec 7",
+			matches: []time.Time{
+				time.Date(2021, time.December, 5, 4, 30, 0, 0, time.UTC),
+			},
+			notMatches: []time.Time{
+				time.Date(2021, time.November, 7, 4, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "day-of-month-or-day-of-week",
+			expr: "0 0 1 * fri",
+			matches: []time.Time{
+				time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2021, time.June, 4, 0, 0, 0, 0, time.UTC),
+			},
+			notMatches: []time.Time{
+				time.Date(2021, time.June, 2, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "macro",
+			expr: "@monthly",
+			matches: []time.Time{
+				time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC),
+			},
+			notMatches: []time.Time{
+				time.Date(2021, time.June, 2, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "too-few-fields",
+			expr: "0 2 * *",
+			err:  "expected 5 or 6 fields, got 4: 0 2 * *",
+		},
+		{
+			name: "out-of-range",
+			expr: "0 24 * * *",
+			err:  "hour: value 24 out of range 0-23",
+		},
+		{
+			name: "bad-name",
+			expr: "0 0 * * funday",
+			err:  "day of week: unrecognized value: funday",
+		},
+		{
+			name: "bad-range",
+			expr: "0 0 * * 5-1",
+			err:  "day of week: invalid range: 5-1",
+		},
+		{
+			name: "bad-step",
+			expr: "*/0 * * * *",
+			err:  "minute: invalid step: */0",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s, err := ParseCronSchedule(c.expr)
+			if c.err != "" {
+				require.EqualError(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, m := range c.matches {
+				require.True(t, cronMatches(s, m), m.String())
+			}
+			for _, m := range c.notMatches {
+				require.False(t, cronMatches(s, m), m.String())
+			}
+		})
+	}
+}
+
+func TestCronScheduleSeconds(t *testing.T) {
+	// Compare against stepping through every second of the day.
+	for _, expr := range []string{"0 2 * * *", "*/7 */13 1,5-9,23 * * *", "59 59 23 * * *", "0 0 0 * * *"} {
+		s, err := ParseCronSchedule(expr)
+		require.NoError(t, err)
+
+		day := time.Date(2021, time.June, 7, 0, 0, 0, 0, time.UTC)
+		var starts []int
+		for sec := 0; sec < 24*3600; sec++ {
+			if cronMatches(s, day.Add(time.Duration(sec)*time.Second)) {
+				starts = append(starts, sec)
+			}
+		}
+
+		for sec := -1; sec < 24*3600; sec++ {
+			next, ok := s.nextSecond(sec)
+			i := 0
+			for i < len(starts) && starts[i] <= sec {
+				i++
+			}
+			if i == len(starts) {
+				require.False(t, ok, "%s next after %d", expr, sec)
+			} else {
+				require.True(t, ok, "%s next after %d", expr, sec)
+				require.Equal(t, starts[i], next, "%s next after %d", expr, sec)
+			}
+
+			if sec < 0 {
+				continue
+			}
+			prev, ok := s.previousSecond(sec)
+			j := len(starts) - 1
+			for j >= 0 && starts[j] > sec {
+				j--
+			}
+			if j < 0 {
+				require.False(t, ok, "%s previous at %d", expr, sec)
+			} else {
+				require.True(t, ok, "%s previous at %d", expr, sec)
+				require.Equal(t, starts[j], prev, "%s previous at %d", expr, sec)
+			}
+		}
+	}
+}
+
+func cronMatches(s CronSchedule, t time.Time) bool {
+	return s.matchesDay(t) &&
+		s.hours&(1<<uint(t.Hour())) != 0 &&
+		s.minutes&(1<<uint(t.Minute())) != 0 &&
+		s.seconds&(1<<uint(t.Second())) != 0
+}
+
+func TestCronScheduleText(t *testing.T) {
+	s, err := ParseCronSchedule("  @Daily ")
+	require.NoError(t, err)
+	require.Equal(t, "@Daily", s.String())
+
+	b, err := s.MarshalText()
+	require.NoError(t, err)
+
+	var parsed CronSchedule
+	require.NoError(t, parsed.UnmarshalText(b))
+	require.Equal(t, s, parsed)
+
+	_, err = CronSchedule{}.MarshalText()
+	require.EqualError(t, err, "schedule was not parsed from a cron expression")
+	require.EqualError(t, parsed.UnmarshalText([]byte("0 2 * *")), "expected 5 or 6 fields, got 4: 0 2 * *")
+}
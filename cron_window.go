@@ -0,0 +1,200 @@
+package timewindow
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+func ParseCronWindow(expr, duration string) (*CronWindow, error) {
+	s, err := ParseCronSchedule(expr)
+	if err != nil {
+		return nil, fmt.Errorf("schedule: %w", err)
+	}
+
+	d, err := parseWindowDuration(duration)
+	if err != nil {
+		return nil, fmt.Errorf("duration: %w", err)
+	}
+
+	return &CronWindow{Schedule: s, Duration: d}, nil
+}
+
+// cronWindowJSON is the JSON and YAML representation of a CronWindow.
+type cronWindowJSON struct {
+	Schedule string `json:"schedule" yaml:"schedule"`
+	Duration string `json:"duration" yaml:"duration"`
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
+
+func newCronWindowJSON(w CronWindow) (cronWindowJSON, error) {
+	schedule, err := w.Schedule.MarshalText()
+	if err != nil {
+		return cronWindowJSON{}, fmt.Errorf("schedule: %w", err)
+	}
+	tz, err := timezoneName(w.Location)
+	if err != nil {
+		return cronWindowJSON{}, err
+	}
+	return cronWindowJSON{Schedule: string(schedule), Duration: w.Duration.String(), Timezone: tz}, nil
+}
+
+// window parses v with the same validation as ParseCronWindow.
+func (v cronWindowJSON) window() (*CronWindow, error) {
+	w, err := ParseCronWindow(v.Schedule, v.Duration)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Location, err = parseTimezone(v.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// MarshalJSON implements json.Marshaler. The window is written as an object
+// such as {"schedule":"0 2 * * 1-5","duration":"3h0m0s","timezone":"UTC"}.
+func (w CronWindow) MarshalJSON() ([]byte, error) {
+	v, err := newCronWindowJSON(w)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The fields are validated like
+// the arguments of ParseCronWindow.
+func (w *CronWindow) UnmarshalJSON(b []byte) error {
+	var v cronWindowJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	return w.set(v)
+}
+
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2 and
+// gopkg.in/yaml.v3.
+func (w CronWindow) MarshalYAML() (interface{}, error) {
+	return newCronWindowJSON(w)
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2,
+// which gopkg.in/yaml.v3 also supports.
+func (w *CronWindow) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v cronWindowJSON
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	return w.set(v)
+}
+
+// set sets w to the window parsed from v.
+func (w *CronWindow) set(v cronWindowJSON) error {
+	parsed, err := v.window()
+	if err != nil {
+		return err
+	}
+	*w = *parsed
+	return nil
+}
+
+// CronWindow is a window that starts at the times of a cron schedule and lasts
+// for Duration. Occurrences overlap if Duration is longer than the time
+// between two starts.
+//
+// Start times that are skipped by a DST transition do not occur. Start times
+// that are repeated occur at their first occurrence.
+type CronWindow struct {
+	Schedule CronSchedule
+	Duration time.Duration
+
+	// Location is the time zone that Schedule is expressed in. If nil, the
+	// window is resolved in the location of the time that is passed in.
+	Location *time.Location
+}
+
+var _ Window = &CronWindow{}
+
+// cronWindowSearchDays is how many days are searched for a matching day. A
+// schedule for February 29 may only match once every eight years.
+const cronWindowSearchDays = 8*366 + 1
+
+// WithinWindow returns true if within the window. It also returns the time until
+// the next window.
+func (w *CronWindow) WithinWindow(now time.Time) WindowResult {
+	return withinOccurrences(w, now)
+}
+
+// NextOccurrence returns the first window that starts after t.
+func (w *CronWindow) NextOccurrence(t time.Time) (Interval, bool) {
+	loc := windowLocation(w.Location, t)
+	local := t.In(loc)
+
+	day := date(local)
+	after := int(TODOf(local).SinceMidnight() / time.Second)
+	for i := 0; i <= cronWindowSearchDays; i++ {
+		if w.Schedule.matchesDay(day) {
+			for {
+				sec, ok := w.Schedule.nextSecond(after)
+				if !ok {
+					break
+				}
+				if start, ok := cronTime(day, sec, loc); ok && start.After(t) {
+					return Interval{Start: start, End: start.Add(w.Duration)}, true
+				}
+				after = sec
+			}
+		}
+		day, after = day.AddDate(0, 0, 1), -1
+	}
+	return Interval{}, false
+}
+
+// PreviousOccurrence returns the last window that starts at or before t.
+func (w *CronWindow) PreviousOccurrence(t time.Time) (Interval, bool) {
+	loc := windowLocation(w.Location, t)
+	local := t.In(loc)
+
+	day := date(local)
+	atOrBefore := int(TODOf(local).SinceMidnight() / time.Second)
+	for i := 0; i <= cronWindowSearchDays; i++ {
+		if w.Schedule.matchesDay(day) {
+			for atOrBefore >= 0 {
+				sec, ok := w.Schedule.previousSecond(atOrBefore)
+				if !ok {
+					break
+				}
+				if start, ok := cronTime(day, sec, loc); ok && !start.After(t) {
+					return Interval{Start: start, End: start.Add(w.Duration)}, true
+				}
+				atOrBefore = sec - 1
+			}
+		}
+		day, atOrBefore = day.AddDate(0, 0, -1), 24*3600-1
+	}
+	return Interval{}, false
+}
+
+// Occurrences returns the windows that overlap the range from from (inclusive)
+// to to (exclusive).
+func (w *CronWindow) Occurrences(from, to time.Time) []Interval {
+	return occurrences(w, from, to)
+}
+
+// Iterate returns an iterator over the windows, beginning with the first one
+// that ends after from.
+func (w *CronWindow) Iterate(from time.Time) *OccurrenceIterator {
+	return newOccurrenceIterator(w, from)
+}
+
+// cronTime returns the instant at which clocks in loc show the given second of
+// the calendar date of day. It returns false if clocks skip that time.
+func cronTime(day time.Time, sec int, loc *time.Location) (time.Time, bool) {
+	h, m, s := splitSecondOfDay(sec)
+	t := wallClock(day, TOD{Hour: h, Minute: m, Second: s}, loc)
+	if t.Hour() != h || t.Minute() != m {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,325 @@
+package timewindow
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronWindow(t *testing.T) {
+	weekdayNights := CronWindow{
+		Schedule: mustParseCronSchedule("0 2 * * 1-5"),
+		Duration: 3 * time.Hour,
+	}
+
+	cases := []struct {
+		name string
+
+		window CronWindow
+		now    time.Time
+
+		result   WindowResult
+		previous Interval
+		next     Interval
+	}{
+		{
+			name:   "before",
+			window: weekdayNights,
+			now:    time.Date(2021, time.June, 7, 1, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.June, 4, 2, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 4, 5, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.June, 7, 2, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 7, 5, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "within",
+			window: weekdayNights,
+			now:    time.Date(2021, time.June, 7, 4, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 22 * time.Hour,
+				TTEnd:   time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.June, 7, 2, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 7, 5, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.June, 8, 2, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 8, 5, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "on-start",
+			window: weekdayNights,
+			now:    time.Date(2021, time.June, 7, 2, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 0,
+				TTEnd:   3 * time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.June, 7, 2, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 7, 5, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.June, 8, 2, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 8, 5, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "weekend",
+			window: weekdayNights,
+			now:    time.Date(2021, time.June, 5, 12, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 38 * time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.June, 4, 2, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 4, 5, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.June, 7, 2, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 7, 5, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "seconds",
+			window: CronWindow{
+				Schedule: mustParseCronSchedule("30 */10 * * * *"),
+				Duration: 20 * time.Second,
+			},
+			now: time.Date(2021, time.June, 7, 4, 10, 40, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 9*time.Minute + 50*time.Second,
+				TTEnd:   10 * time.Second,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.June, 7, 4, 10, 30, 0, time.UTC),
+				End:   time.Date(2021, time.June, 7, 4, 10, 50, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.June, 7, 4, 20, 30, 0, time.UTC),
+				End:   time.Date(2021, time.June, 7, 4, 20, 50, 0, time.UTC),
+			},
+		},
+		{
+			name: "leap-day",
+			window: CronWindow{
+				Schedule: mustParseCronSchedule("0 0 29 2 *"),
+				Duration: 24 * time.Hour,
+			},
+			now: time.Date(2097, time.January, 1, 0, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: time.Date(2104, time.February, 29, 0, 0, 0, 0, time.UTC).Sub(time.Date(2097, time.January, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			previous: Interval{
+				Start: time.Date(2096, time.February, 29, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2096, time.March, 1, 0, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2104, time.February, 29, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2104, time.March, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "location",
+			window: CronWindow{
+				Schedule: mustParseCronSchedule("0 2 * * *"),
+				Duration: time.Hour,
+				Location: berlin,
+			},
+			now: time.Date(2021, time.June, 7, 0, 30, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 23*time.Hour + 30*time.Minute,
+				TTEnd:   30 * time.Minute,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.June, 7, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 7, 1, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.June, 8, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 8, 1, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "dst-skipped",
+			window: CronWindow{
+				Schedule: mustParseCronSchedule("30 2 * * *"),
+				Duration: time.Hour,
+			},
+			now: time.Date(2021, time.March, 14, 1, 0, 0, 0, newYork),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 24*time.Hour + 30*time.Minute,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.March, 13, 2, 30, 0, 0, newYork),
+				End:   time.Date(2021, time.March, 13, 3, 30, 0, 0, newYork),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.March, 15, 2, 30, 0, 0, newYork),
+				End:   time.Date(2021, time.March, 15, 3, 30, 0, 0, newYork),
+			},
+		},
+		{
+			name: "dst-repeated",
+			window: CronWindow{
+				Schedule: mustParseCronSchedule("30 1 * * *"),
+				Duration: 15 * time.Minute,
+			},
+			now: time.Date(2021, time.November, 7, 5, 50, 0, 0, time.UTC).In(newYork), // 01:50 EDT
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 24*time.Hour + 40*time.Minute,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.November, 7, 5, 30, 0, 0, time.UTC),
+				End:   time.Date(2021, time.November, 7, 5, 45, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.November, 8, 6, 30, 0, 0, time.UTC),
+				End:   time.Date(2021, time.November, 8, 6, 45, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result := c.window.WithinWindow(c.now)
+			require.Equal(t, c.result.Within, result.Within, "Within")
+			require.Equal(t, c.result.TTStart.String(), result.TTStart.String(), "TTStart")
+			require.Equal(t, c.result.TTEnd.String(), result.TTEnd.String(), "TTEnd")
+
+			previous, ok := c.window.PreviousOccurrence(c.now)
+			require.True(t, ok)
+			require.Equal(t, c.previous.Start.UTC().String(), previous.Start.UTC().String(), "previous start")
+			require.Equal(t, c.previous.End.UTC().String(), previous.End.UTC().String(), "previous end")
+
+			next, ok := c.window.NextOccurrence(c.now)
+			require.True(t, ok)
+			require.Equal(t, c.next.Start.UTC().String(), next.Start.UTC().String(), "next start")
+			require.Equal(t, c.next.End.UTC().String(), next.End.UTC().String(), "next end")
+		})
+	}
+}
+
+func TestCronWindowNever(t *testing.T) {
+	w := CronWindow{Schedule: mustParseCronSchedule("0 0 30 2 *"), Duration: time.Hour}
+	now := time.Date(2021, time.June, 7, 0, 0, 0, 0, time.UTC)
+
+	_, ok := w.NextOccurrence(now)
+	require.False(t, ok)
+	_, ok = w.PreviousOccurrence(now)
+	require.False(t, ok)
+	require.Equal(t, WindowResult{TTStart: Never, TSStart: Never, TSEnd: Never}, w.WithinWindow(now))
+}
+
+func TestCronWindowOverlapping(t *testing.T) {
+	w, err := ParseCronWindow("0 * * * *", "3h")
+	require.NoError(t, err)
+	now := time.Date(2021, time.June, 8, 1, 30, 0, 0, time.UTC)
+
+	result := w.WithinWindow(now)
+	require.True(t, result.Within)
+	require.Equal(t, (30 * time.Minute).String(), result.TTStart.String(), "TTStart")
+	require.Equal(t, (30 * time.Minute).String(), result.TSStart.String(), "TSStart")
+	require.Equal(t, (30 * time.Minute).String(), result.TSEnd.String(), "TSEnd")
+	end, ok := previousEndTime(w, now)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2021, time.June, 8, 1, 0, 0, 0, time.UTC).String(), end.String())
+}
+
+func TestParseCronWindow(t *testing.T) {
+	w, err := ParseCronWindow("0 2 * * 1-5", "3h")
+	require.NoError(t, err)
+	require.Equal(t, 3*time.Hour, w.Duration)
+
+	_, err = ParseCronWindow("0 2 * *", "3h")
+	require.EqualError(t, err, "schedule: expected 5 or 6 fields, got 4: 0 2 * *")
+
+	_, err = ParseCronWindow("0 2 * * *", "3x")
+	require.Error(t, err)
+
+	_, err = ParseCronWindow("0 2 * * *", "-3h")
+	require.EqualError(t, err, "duration: must be positive")
+
+	_, err = ParseCronWindow("0 2 * * *", "0s")
+	require.EqualError(t, err, "duration: must be positive")
+}
+
+func mustParseCronSchedule(expr string) CronSchedule {
+	s, err := ParseCronSchedule(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func TestCronWindowJSON(t *testing.T) {
+	w := CronWindow{Schedule: mustParseCronSchedule("0  2 * *   1-5"), Duration: 3 * time.Hour, Location: berlin}
+
+	b, err := json.Marshal(w)
+	require.NoError(t, err)
+	require.Equal(t, `{"schedule":"0 2 * * 1-5","duration":"3h0m0s","timezone":"Europe/Berlin"}`, string(b))
+
+	var parsed CronWindow
+	require.NoError(t, json.Unmarshal(b, &parsed))
+	require.Equal(t, mustParseCronSchedule("0 2 * * 1-5"), parsed.Schedule)
+	require.Equal(t, w.Duration, parsed.Duration)
+	require.Equal(t, w.Location, parsed.Location)
+}
+
+func TestCronWindowJSONSadPath(t *testing.T) {
+	cases := []struct {
+		json string
+		err  string
+	}{
+		{
+			json: `{"schedule":"0 2 * *","duration":"3h"}`,
+			err:  "schedule: expected 5 or 6 fields, got 4: 0 2 * *",
+		},
+		{
+			json: `{"schedule":"0 2 * * 1-5","duration":"0s"}`,
+			err:  "duration: must be positive",
+		},
+		{
+			json: `{"schedule":"0 2 * * 1-5","duration":"3h","timezone":"Nowhere/Special"}`,
+			err:  "timezone: unknown time zone Nowhere/Special",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.json, func(t *testing.T) {
+			var w CronWindow
+			require.EqualError(t, json.Unmarshal([]byte(c.json), &w), c.err)
+		})
+	}
+
+	_, err := json.Marshal(CronWindow{Duration: time.Hour})
+	require.Contains(t, err.Error(), "schedule: schedule was not parsed from a cron expression")
+}
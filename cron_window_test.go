@@ -0,0 +1,25 @@
+package timewindow_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nstogner/timewindow"
+)
+
+func ExampleCronWindow() {
+	window, err := timewindow.ParseCronWindow("0 2 * * 1-5", "3h")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	from := time.Date(2021, time.June, 4, 0, 0, 0, 0, time.UTC)
+	for _, o := range window.Occurrences(from, from.AddDate(0, 0, 5)) {
+		fmt.Println(o.Start.Format(time.RFC1123), "-", o.End.Format(time.RFC1123))
+	}
+	// Output:
+	// Fri, 04 Jun 2021 02:00:00 UTC - Fri, 04 Jun 2021 05:00:00 UTC
+	// Mon, 07 Jun 2021 02:00:00 UTC - Mon, 07 Jun 2021 05:00:00 UTC
+	// Tue, 08 Jun 2021 02:00:00 UTC - Tue, 08 Jun 2021 05:00:00 UTC
+}
@@ -0,0 +1,53 @@
+package timewindow
+
+import "time"
+
+// dayWindow is a window from start to end on the days that match. It holds
+// the occurrence logic that TODMonthWindow and TODNthWeekdayWindow share,
+// which only differ in how days are matched. Windows that cross midnight end
+// on the following day.
+type dayWindow struct {
+	start    TOD
+	end      TOD
+	location *time.Location
+	matches  func(day time.Time) bool
+}
+
+// dayWindowSearchDays is how many days are searched for a matching day. No
+// two matching days are further apart than a year.
+const dayWindowSearchDays = 366
+
+// nextOccurrence returns the first window that starts after t on a matching
+// day.
+func (w dayWindow) nextOccurrence(t time.Time) (Interval, bool) {
+	loc := windowLocation(w.location, t)
+	return nextDayOccurrence(t, loc, dayWindowSearchDays, func(day time.Time) (Interval, bool) {
+		return w.occurrence(day, loc)
+	})
+}
+
+// previousOccurrence returns the last window that starts at or before t on a
+// matching day.
+func (w dayWindow) previousOccurrence(t time.Time) (Interval, bool) {
+	loc := windowLocation(w.location, t)
+	return previousDayOccurrence(t, loc, dayWindowSearchDays, func(day time.Time) (Interval, bool) {
+		return w.occurrence(day, loc)
+	})
+}
+
+// occurrence returns the window that starts on day, if day matches.
+func (w dayWindow) occurrence(day time.Time, loc *time.Location) (Interval, bool) {
+	if !w.matches(day) {
+		return Interval{}, false
+	}
+
+	start := wallClock(day, w.start, loc)
+	if w.end.Before(w.start) {
+		day = day.AddDate(0, 0, 1)
+	}
+	end := wallClock(day, w.end, loc)
+	if end.Before(start) {
+		end = start
+	}
+	return Interval{Start: start, End: end}, true
+}
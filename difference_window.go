@@ -0,0 +1,126 @@
+package timewindow
+
+import "time"
+
+// Difference returns a window that is open whenever base is open and none of
+// the exclusions are.
+func Difference(base Window, exclude ...Window) *DifferenceWindow {
+	return &DifferenceWindow{Base: base, Exclude: exclude}
+}
+
+// DifferenceWindow is open whenever Base is open and none of its exclusions
+// are, for example a nightly window with a change freeze layered on top. An
+// occurrence of Base that is partially excluded is split into the parts that
+// remain open.
+type DifferenceWindow struct {
+	Base Window
+
+	// Exclude holds windows during which Base is closed.
+	Exclude []Window
+	// ExcludeRanges holds absolute periods during which Base is closed.
+	ExcludeRanges []Interval
+}
+
+var _ Window = &DifferenceWindow{}
+
+// WithinWindow returns true if within the base window and outside of all
+// exclusions. It also returns the time until the next window.
+func (d *DifferenceWindow) WithinWindow(now time.Time) WindowResult {
+	return withinOccurrences(d, now)
+}
+
+// NextOccurrence returns the first non-excluded window that starts after t.
+func (d *DifferenceWindow) NextOccurrence(t time.Time) (Interval, bool) {
+	excl := d.exclusions()
+
+	b, ok := d.Base.PreviousOccurrence(t)
+	if !ok || !b.End.After(t) {
+		b, ok = nextNonEmptyOccurrence(d.Base, t)
+	}
+	for ok && b.Start.Sub(t) <= horizon {
+		var (
+			next  Interval
+			found bool
+		)
+		eachPiece(b, excl, func(p Interval) bool {
+			if p.Start.After(t) {
+				next, found = p, true
+				return false
+			}
+			return true
+		})
+		if found {
+			return next, true
+		}
+		b, ok = nextNonEmptyOccurrence(d.Base, b.Start)
+	}
+	return Interval{}, false
+}
+
+// PreviousOccurrence returns the last non-excluded window that starts at or
+// before t.
+func (d *DifferenceWindow) PreviousOccurrence(t time.Time) (Interval, bool) {
+	excl := d.exclusions()
+
+	b, ok := d.Base.PreviousOccurrence(t)
+	for ok && t.Sub(b.End) <= horizon {
+		var (
+			prev  Interval
+			found bool
+		)
+		eachPiece(b, excl, func(p Interval) bool {
+			if p.Start.After(t) {
+				return false
+			}
+			prev, found = p, true
+			return true
+		})
+		if found {
+			return prev, true
+		}
+		b, ok = d.Base.PreviousOccurrence(b.Start.Add(-1))
+	}
+	return Interval{}, false
+}
+
+// Occurrences returns the non-excluded windows that overlap the range from
+// from (inclusive) to to (exclusive).
+func (d *DifferenceWindow) Occurrences(from, to time.Time) []Interval {
+	return occurrences(d, from, to)
+}
+
+// Iterate returns an iterator over the non-excluded windows, beginning with the
+// first one that ends after from.
+func (d *DifferenceWindow) Iterate(from time.Time) *OccurrenceIterator {
+	return newOccurrenceIterator(d, from)
+}
+
+// exclusions returns a window that is open whenever any exclusion is.
+func (d *DifferenceWindow) exclusions() *UnionWindow {
+	u := &UnionWindow{Windows: append([]Window(nil), d.Exclude...)}
+	for _, r := range d.ExcludeRanges {
+		u.Windows = append(u.Windows, &AbsoluteWindow{Start: r.Start, End: r.End})
+	}
+	return u
+}
+
+// eachPiece calls fn, in order, for every part of the interval b that is not
+// covered by excl until fn returns false.
+func eachPiece(b Interval, excl *UnionWindow, fn func(Interval) bool) {
+	p := b.Start
+	for p.Before(b.End) {
+		if e, ok := excl.cover(p); ok {
+			p = e.End
+			continue
+		}
+
+		end := b.End
+		if next, ok := excl.nextStart(p); ok && next.Before(end) {
+			end = next
+		}
+		if !fn(Interval{Start: p, End: end}) {
+			return
+		}
+		p = end
+	}
+}
@@ -0,0 +1,217 @@
+package timewindow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDifferenceWindow(t *testing.T) {
+	nightly := &TODWindow{Start: TOD{Hour: 1}, End: TOD{Hour: 5}}
+	weeknights := &TODWeekWindow{
+		Start: TOD{Hour: 1},
+		End:   TOD{Hour: 5},
+		Weekdays: Weekdays{
+			time.Monday:    true,
+			time.Tuesday:   true,
+			time.Wednesday: true,
+			time.Thursday:  true,
+			time.Friday:    true,
+		},
+	}
+	freeze := Interval{
+		Start: time.Date(2026, time.November, 26, 12, 0, 0, 0, time.UTC),
+		End:   time.Date(2026, time.November, 30, 3, 0, 0, 0, time.UTC),
+	}
+
+	cases := []struct {
+		name string
+
+		window *DifferenceWindow
+		now    time.Time
+
+		result   WindowResult
+		previous Interval
+		next     Interval
+	}{
+		{
+			name:   "before-exclusion",
+			window: Difference(nightly, &TODWindow{Start: TOD{Hour: 2}, End: TOD{Hour: 3}}),
+			now:    time.Date(2000, time.January, 1, 1, 30, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: time.Hour + 30*time.Minute,
+				TTEnd:   30 * time.Minute,
+			},
+			previous: Interval{
+				Start: time.Date(2000, time.January, 1, 1, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 2, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2000, time.January, 1, 3, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 5, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "within-exclusion",
+			window: Difference(nightly, &TODWindow{Start: TOD{Hour: 2}, End: TOD{Hour: 3}}),
+			now:    time.Date(2000, time.January, 1, 2, 30, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 30 * time.Minute,
+			},
+			previous: Interval{
+				Start: time.Date(2000, time.January, 1, 1, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 2, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2000, time.January, 1, 3, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 5, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "after-exclusion",
+			window: Difference(nightly, &TODWindow{Start: TOD{Hour: 2}, End: TOD{Hour: 3}}),
+			now:    time.Date(2000, time.January, 1, 4, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 21 * time.Hour,
+				TTEnd:   time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2000, time.January, 1, 3, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 5, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2000, time.January, 2, 1, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "before-freeze",
+			window: &DifferenceWindow{Base: weeknights, ExcludeRanges: []Interval{freeze}},
+			now:    time.Date(2026, time.November, 26, 2, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 97 * time.Hour,
+				TTEnd:   3 * time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2026, time.November, 26, 1, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, time.November, 26, 5, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2026, time.November, 30, 3, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, time.November, 30, 5, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "during-freeze",
+			window: &DifferenceWindow{Base: weeknights, ExcludeRanges: []Interval{freeze}},
+			now:    time.Date(2026, time.November, 27, 2, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 73 * time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2026, time.November, 26, 1, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, time.November, 26, 5, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2026, time.November, 30, 3, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, time.November, 30, 5, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "freeze-and-weekly-exclusion",
+			window: &DifferenceWindow{
+				Base:          nightly,
+				Exclude:       []Window{&TODWeekWindow{Start: TOD{Hour: 0}, End: TOD{Hour: 2}, Weekdays: Weekdays{time.Tuesday: true}}},
+				ExcludeRanges: []Interval{freeze},
+			},
+			now: time.Date(2026, time.November, 30, 4, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 22 * time.Hour,
+				TTEnd:   time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2026, time.November, 30, 3, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, time.November, 30, 5, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2026, time.December, 1, 2, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, time.December, 1, 5, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result := c.window.WithinWindow(c.now)
+			require.Equal(t, c.result.Within, result.Within)
+			require.Equal(t, c.result.TTStart.String(), result.TTStart.String())
+			require.Equal(t, c.result.TTEnd.String(), result.TTEnd.String())
+
+			previous, ok := c.window.PreviousOccurrence(c.now)
+			require.True(t, ok)
+			require.Equal(t, c.previous.Start.String(), previous.Start.String())
+			require.Equal(t, c.previous.End.String(), previous.End.String())
+
+			next, ok := c.window.NextOccurrence(c.now)
+			require.True(t, ok)
+			require.Equal(t, c.next.Start.String(), next.Start.String())
+			require.Equal(t, c.next.End.String(), next.End.String())
+		})
+	}
+}
+
+func TestDifferenceWindowFullyExcluded(t *testing.T) {
+	window := Difference(
+		&TODWindow{Start: TOD{Hour: 1}, End: TOD{Hour: 5}},
+		&TODWindow{Start: TOD{Hour: 0}, End: TOD{Hour: 6}},
+	)
+	now := time.Date(2000, time.January, 1, 2, 0, 0, 0, time.UTC)
+
+	result := window.WithinWindow(now)
+	require.False(t, result.Within)
+	require.Equal(t, Never, result.TTStart)
+
+	_, ok := window.NextOccurrence(now)
+	require.False(t, ok)
+	_, ok = window.PreviousOccurrence(now)
+	require.False(t, ok)
+}
+
+func TestDifferenceWindowOccurrences(t *testing.T) {
+	window := Difference(
+		&TODWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 4}},
+		&TODWindow{Start: TOD{Hour: 0}, End: TOD{Hour: 1}},
+	)
+
+	occurrences := window.Occurrences(
+		time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2000, time.January, 2, 12, 0, 0, 0, time.UTC),
+	)
+	require.Equal(t, []Interval{
+		{
+			Start: time.Date(2000, time.January, 1, 1, 0, 0, 0, time.UTC),
+			End:   time.Date(2000, time.January, 1, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			Start: time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+			End:   time.Date(2000, time.January, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Start: time.Date(2000, time.January, 2, 1, 0, 0, 0, time.UTC),
+			End:   time.Date(2000, time.January, 2, 4, 0, 0, 0, time.UTC),
+		},
+	}, occurrences)
+}
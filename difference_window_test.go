@@ -0,0 +1,31 @@
+package timewindow_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nstogner/timewindow"
+)
+
+func ExampleDifferenceWindow() {
+	nightly, err := timewindow.ParseTODWindow("01:00", "05:00")
+	if err != nil {
+		log.Fatal(err)
+	}
+	window := &timewindow.DifferenceWindow{
+		Base: nightly,
+		ExcludeRanges: []timewindow.Interval{{
+			Start: time.Date(2026, time.November, 26, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2026, time.November, 30, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	now := time.Date(2026, time.November, 25, 12, 0, 0, 0, time.UTC)
+	result := window.WithinWindow(now)
+	fmt.Println("within: ", result.Within)
+	fmt.Println("untilStart: ", result.TTStart)
+	// Output:
+	// within:  false
+	// untilStart:  109h0m0s
+}
@@ -0,0 +1,826 @@
+package timewindow
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ICalendarEvent is a window parsed from an iCalendar (RFC 5545) VEVENT. It is
+// open during the first occurrence from Start to End and during the
+// occurrences of Recurrence that start between Start and Until, unless they
+// are excluded.
+type ICalendarEvent struct {
+	// Recurrence is one of *TODWindow, *TODWeekWindow, *TODMonthWindow or
+	// *TODNthWeekdayWindow.
+	Recurrence Window
+
+	// Start is the start of the first occurrence (DTSTART).
+	Start time.Time
+	// End is the end of the first occurrence. The first occurrence is part
+	// of the event even if Recurrence does not occur at Start, for example
+	// if DTSTART falls on a weekday that is not in BYDAY. If End is zero,
+	// only the occurrences of Recurrence are used.
+	End time.Time
+	// Until is the latest time that an occurrence may start at. The zero
+	// time means that the event repeats forever.
+	Until time.Time
+	// ExDates holds the starts of occurrences that are excluded (EXDATE).
+	ExDates []time.Time
+
+	// Floating is true if the times of the event are not bound to a time
+	// zone. Start, Until and ExDates then hold wall clock times in UTC which
+	// are resolved in the location of the time that is passed in, and the
+	// Location of Recurrence is nil.
+	Floating bool
+}
+
+var _ Window = &ICalendarEvent{}
+
+// WithinWindow returns true if within an occurrence of the event. It also
+// returns the time until the next occurrence.
+func (e *ICalendarEvent) WithinWindow(now time.Time) WindowResult {
+	return withinOccurrences(e, now)
+}
+
+// NextOccurrence returns the first occurrence of the event that starts after t.
+func (e *ICalendarEvent) NextOccurrence(t time.Time) (Interval, bool) {
+	if first, ok := e.first(t); ok && t.Before(first.Start) {
+		return first, true
+	}
+	if start := e.resolve(e.Start, t); t.Before(start) {
+		t = start.Add(-1)
+		if !e.End.IsZero() {
+			// The first occurrence is excluded.
+			t = start
+		}
+	}
+
+	o, ok := e.Recurrence.NextOccurrence(t)
+	for ok && e.excluded(o, t) {
+		o, ok = e.Recurrence.NextOccurrence(o.Start)
+	}
+	if !ok || (!e.Until.IsZero() && o.Start.After(e.resolve(e.Until, t))) {
+		return Interval{}, false
+	}
+	return o, true
+}
+
+// PreviousOccurrence returns the last occurrence of the event that starts at or
+// before t.
+func (e *ICalendarEvent) PreviousOccurrence(t time.Time) (Interval, bool) {
+	if !e.Until.IsZero() {
+		if until := e.resolve(e.Until, t); t.After(until) {
+			t = until
+		}
+	}
+
+	o, ok := e.Recurrence.PreviousOccurrence(t)
+	for ok && e.excluded(o, t) {
+		o, ok = e.Recurrence.PreviousOccurrence(o.Start.Add(-1))
+	}
+	if !ok || !o.Start.After(e.resolve(e.Start, t)) {
+		if first, ok := e.first(t); ok && !first.Start.After(t) {
+			return first, true
+		}
+	}
+	if !ok || o.Start.Before(e.resolve(e.Start, t)) {
+		return Interval{}, false
+	}
+	return o, true
+}
+
+// Occurrences returns the occurrences of the event that overlap the range from
+// from (inclusive) to to (exclusive).
+func (e *ICalendarEvent) Occurrences(from, to time.Time) []Interval {
+	return occurrences(e, from, to)
+}
+
+// Iterate returns an iterator over the occurrences of the event, beginning with
+// the first one that ends after from.
+func (e *ICalendarEvent) Iterate(from time.Time) *OccurrenceIterator {
+	return newOccurrenceIterator(e, from)
+}
+
+// resolve returns the instant of an event time, resolving floating times in
+// the location of now.
+func (e *ICalendarEvent) resolve(t, now time.Time) time.Time {
+	if !e.Floating {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), now.Location())
+}
+
+// first returns the first occurrence of the event from Start to End, resolved
+// in the location of now. It returns false if End is zero or if the first
+// occurrence is excluded.
+func (e *ICalendarEvent) first(now time.Time) (Interval, bool) {
+	if e.End.IsZero() {
+		return Interval{}, false
+	}
+	o := Interval{Start: e.resolve(e.Start, now), End: e.resolve(e.End, now)}
+	if e.excluded(o, now) {
+		return Interval{}, false
+	}
+	return o, true
+}
+
+func (e *ICalendarEvent) excluded(o Interval, now time.Time) bool {
+	for _, d := range e.ExDates {
+		if e.resolve(d, now).Equal(o.Start) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseICalendar parses the VEVENT components of an iCalendar (RFC 5545)
+// stream.
+//
+// Only the subset of RFC 5545 that maps onto the window types of this package
+// is supported: events with a DTSTART date-time (UTC, floating or with a TZID
+// that is an IANA time zone name), a DTEND or DURATION (of less than 24 hours
+// for daily and monthly events), and an optional RRULE with a FREQ of DAILY,
+// WEEKLY or MONTHLY, an INTERVAL of 1 and the BYDAY, BYMONTHDAY, COUNT (of at
+// most 10000) and UNTIL parts. As in RFC 5545, DTSTART is the first occurrence
+// even if it does not match the rule. EXDATE is supported and VTIMEZONE
+// components are ignored. Anything else results in an error.
+func ParseICalendar(r io.Reader) ([]*ICalendarEvent, error) {
+	lines, err := unfoldICalendar(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events []*ICalendarEvent
+		props  []icalendarProperty
+		depth  int
+	)
+	for _, line := range lines {
+		p, err := parseICalendarProperty(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case p.name == "BEGIN" && depth == 0 && strings.EqualFold(p.value, "VEVENT"):
+			depth, props = 1, nil
+		case p.name == "BEGIN" && depth > 0:
+			depth++
+		case p.name == "END" && depth == 1:
+			e, err := newICalendarEvent(props)
+			if err != nil {
+				return nil, fmt.Errorf("event %d: %w", len(events)+1, err)
+			}
+			events = append(events, e)
+			depth = 0
+		case p.name == "END" && depth > 1:
+			depth--
+		case depth == 1:
+			props = append(props, p)
+		}
+	}
+
+	return events, nil
+}
+
+// FormatICalendar returns an iCalendar (RFC 5545) VCALENDAR with a single
+// VEVENT for the window, with a DTSTAMP of the current time. The window must
+// be an *ICalendarEvent, *TODWindow, *TODWeekWindow, *TODMonthWindow or
+// *TODNthWeekdayWindow. The first occurrence (DTSTART) of recurring windows is
+// their first occurrence that starts at or after start.
+//
+// Windows with a Location are written with a TZID of the location name and
+// windows without one are written with floating times. No VTIMEZONE
+// components are written, so an error is returned if the Location is not an
+// IANA time zone, such as one created with time.FixedZone.
+func FormatICalendar(w Window, start time.Time) (string, error) {
+	e, ok := w.(*ICalendarEvent)
+	if !ok {
+		loc, err := recurrenceLocation(w)
+		if err != nil {
+			return "", err
+		}
+		o, ok := w.NextOccurrence(start.Add(-1))
+		if !ok {
+			return "", fmt.Errorf("window does not occur after %v", start)
+		}
+
+		e = &ICalendarEvent{Recurrence: w, Start: o.Start, End: o.End}
+		if loc == nil {
+			e.Floating = true
+			e.Start, e.End = naiveTime(o.Start), naiveTime(o.End)
+		}
+	}
+
+	rrule, startTOD, endTOD, length, err := formatRRule(e.Recurrence)
+	if err != nil {
+		return "", err
+	}
+	loc, _ := recurrenceLocation(e.Recurrence)
+	if loc == nil && !e.Floating {
+		return "", errors.New("recurrence without a location must be floating")
+	}
+	if _, err := timezoneName(loc); err != nil {
+		return "", err
+	}
+
+	dtstartParams, dtstart := formatICalendarTime(e.Start, loc, e.Floating)
+	if length == 0 {
+		length = endTOD.Sub(startTOD)
+		if length < 0 {
+			length += 24 * time.Hour
+		}
+	}
+	duration := formatICalendarDuration(length)
+
+	var b strings.Builder
+	writeLine := func(line string) {
+		b.WriteString(line)
+		b.WriteString("\r\n")
+	}
+
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//nstogner//timewindow//EN")
+	writeLine("BEGIN:VEVENT")
+
+	h := fnv.New64a()
+	fmt.Fprint(h, dtstartParams, dtstart, duration, rrule)
+	writeLine(fmt.Sprintf("UID:%x@timewindow", h.Sum64()))
+	writeLine("DTSTAMP:" + time.Now().UTC().Format(icalendarUTCLayout))
+
+	writeLine("DTSTART" + dtstartParams + ":" + dtstart)
+	writeLine("DURATION:" + duration)
+
+	single := !e.Until.IsZero() && e.Until.Equal(e.Start)
+	if _, daily := e.Recurrence.(*TODWindow); !single || !daily {
+		if !e.Until.IsZero() {
+			_, until := formatICalendarTime(e.Until, time.UTC, e.Floating)
+			rrule += ";UNTIL=" + until
+		}
+		writeLine("RRULE:" + rrule)
+	}
+
+	if len(e.ExDates) > 0 {
+		var values []string
+		for _, d := range e.ExDates {
+			_, v := formatICalendarTime(d, loc, e.Floating)
+			values = append(values, v)
+		}
+		writeLine("EXDATE" + dtstartParams + ":" + strings.Join(values, ","))
+	}
+
+	writeLine("END:VEVENT")
+	writeLine("END:VCALENDAR")
+
+	return b.String(), nil
+}
+
+// maxICalendarCount is the largest supported COUNT of an RRULE. The
+// occurrences are enumerated when an event is parsed to find the last one, so
+// a larger COUNT would make ParseICalendar slow.
+const maxICalendarCount = 10000
+
+const (
+	icalendarLocalLayout = "20060102T150405"
+	icalendarUTCLayout   = "20060102T150405Z"
+	icalendarDateLayout  = "20060102"
+)
+
+var weekdayToICalendar = [...]string{
+	time.Sunday:    "SU",
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+}
+
+type icalendarProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// unfoldICalendar returns the content lines of an iCalendar stream with folded
+// lines joined.
+func unfoldICalendar(r io.Reader) ([]string, error) {
+	var lines []string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("reading: %w", err)
+	}
+	return lines, nil
+}
+
+// parseICalendarProperty parses a content line such as
+// "DTSTART;TZID=Europe/Berlin:20260103T220000".
+func parseICalendarProperty(line string) (icalendarProperty, error) {
+	var (
+		parts  []string
+		quoted bool
+		last   int
+		value  = -1
+	)
+	for i, c := range line {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == ';' && !quoted:
+			parts = append(parts, line[last:i])
+			last = i + 1
+		case c == ':' && !quoted:
+			parts = append(parts, line[last:i])
+			value = i + 1
+		}
+		if value >= 0 {
+			break
+		}
+	}
+	if value < 0 {
+		return icalendarProperty{}, fmt.Errorf("invalid content line: %s", line)
+	}
+
+	p := icalendarProperty{
+		name:   strings.ToUpper(parts[0]),
+		params: make(map[string]string),
+		value:  line[value:],
+	}
+	for _, param := range parts[1:] {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 {
+			return icalendarProperty{}, fmt.Errorf("invalid parameter in content line: %s", line)
+		}
+		p.params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+	}
+	return p, nil
+}
+
+func newICalendarEvent(props []icalendarProperty) (*ICalendarEvent, error) {
+	var (
+		dtstart, dtend, duration, rrule *icalendarProperty
+		exdates                         []icalendarProperty
+	)
+	for i := range props {
+		p := &props[i]
+		switch p.name {
+		case "DTSTART":
+			dtstart = p
+		case "DTEND":
+			dtend = p
+		case "DURATION":
+			duration = p
+		case "RRULE":
+			if rrule != nil {
+				return nil, errors.New("multiple RRULEs are not supported")
+			}
+			rrule = p
+		case "EXDATE":
+			exdates = append(exdates, *p)
+		}
+	}
+	if dtstart == nil {
+		return nil, errors.New("missing DTSTART")
+	}
+	if dtend != nil && duration != nil {
+		return nil, errors.New("both DTEND and DURATION are set")
+	}
+
+	start, loc, floating, err := parseICalendarTime(*dtstart)
+	if err != nil {
+		return nil, fmt.Errorf("DTSTART: %w", err)
+	}
+
+	// The length of the event in wall clock time.
+	var length time.Duration
+	switch {
+	case dtend != nil:
+		end, _, _, err := parseICalendarTime(*dtend)
+		if err != nil {
+			return nil, fmt.Errorf("DTEND: %w", err)
+		}
+		if !floating {
+			end = end.In(loc)
+		}
+		length = naiveTime(end).Sub(naiveTime(start))
+	case duration != nil:
+		length, err = parseICalendarDuration(duration.value)
+		if err != nil {
+			return nil, fmt.Errorf("DURATION: %w", err)
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("unsupported length of %v (must not be negative)", length)
+	}
+
+	startTOD := TODOf(start)
+	endTOD := startTOD.Add(length)
+
+	var recurrenceLoc *time.Location
+	if !floating {
+		recurrenceLoc = loc
+	}
+
+	e := &ICalendarEvent{Start: start, End: naiveTime(start).Add(length), Floating: floating}
+	if !floating {
+		e.End = time.Date(e.End.Year(), e.End.Month(), e.End.Day(), e.End.Hour(), e.End.Minute(), e.End.Second(), e.End.Nanosecond(), loc)
+	}
+	if rrule == nil {
+		e.Recurrence = &TODWindow{Start: startTOD, End: endTOD, Location: recurrenceLoc}
+		if length >= 24*time.Hour {
+			// Daily windows are shorter than a day, so a long event repeats
+			// weekly on its own weekday instead.
+			e.Recurrence = &TODWeekWindow{Start: startTOD, End: endTOD, Days: NewWeekdaySet(start.Weekday()), Location: recurrenceLoc}
+		}
+		e.Until = start
+		return e, setRecurrenceLength(e.Recurrence, length)
+	}
+
+	count, err := e.applyRRule(rrule.value, startTOD, endTOD, recurrenceLoc)
+	if err != nil {
+		return nil, fmt.Errorf("RRULE: %w", err)
+	}
+	if err := setRecurrenceLength(e.Recurrence, length); err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		// COUNT includes the occurrences that are excluded by EXDATE.
+		at := start
+		if floating {
+			at = naiveTime(start)
+		}
+		var until time.Time
+		it := e.Iterate(at)
+		for i := 0; i < count; i++ {
+			o, ok := it.Next()
+			if !ok {
+				break
+			}
+			until = o.Start
+		}
+		e.Until = until
+	}
+
+	for _, p := range exdates {
+		for _, v := range strings.Split(p.value, ",") {
+			p.value = v
+			d, _, _, err := parseICalendarTime(p)
+			if err != nil {
+				return nil, fmt.Errorf("EXDATE: %w", err)
+			}
+			e.ExDates = append(e.ExDates, d)
+		}
+	}
+
+	return e, nil
+}
+
+// applyRRule sets the recurrence and end of the event from an RRULE value. It
+// returns the COUNT of the rule, if any.
+func (e *ICalendarEvent) applyRRule(value string, start, end TOD, loc *time.Location) (int, error) {
+	parts := make(map[string]string)
+	for _, part := range strings.Split(value, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return 0, fmt.Errorf("invalid part: %s", part)
+		}
+		key := strings.ToUpper(kv[0])
+		switch key {
+		case "FREQ", "INTERVAL", "COUNT", "UNTIL", "BYDAY", "BYMONTHDAY", "WKST":
+		default:
+			return 0, fmt.Errorf("unsupported part: %s", key)
+		}
+		parts[key] = strings.ToUpper(kv[1])
+	}
+
+	if interval, ok := parts["INTERVAL"]; ok && interval != "1" {
+		return 0, fmt.Errorf("unsupported INTERVAL: %s", interval)
+	}
+
+	var count int
+	if c, ok := parts["COUNT"]; ok {
+		if _, ok := parts["UNTIL"]; ok {
+			return 0, errors.New("both COUNT and UNTIL are set")
+		}
+		n, err := strconv.Atoi(c)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("invalid COUNT: %s", c)
+		}
+		if n > maxICalendarCount {
+			return 0, fmt.Errorf("unsupported COUNT: %s (must be at most %d)", c, maxICalendarCount)
+		}
+		count = n
+	}
+	if u, ok := parts["UNTIL"]; ok {
+		var (
+			until time.Time
+			err   error
+		)
+		if len(u) == len(icalendarDateLayout) {
+			// A date includes the whole day.
+			until, err = time.Parse(icalendarDateLayout, u)
+			until = until.AddDate(0, 0, 1).Add(-1)
+		} else {
+			until, _, _, err = parseICalendarTime(icalendarProperty{value: u})
+		}
+		if err != nil {
+			return 0, fmt.Errorf("UNTIL: %w", err)
+		}
+		if !e.Floating && !strings.HasSuffix(u, "Z") {
+			until = time.Date(until.Year(), until.Month(), until.Day(), until.Hour(), until.Minute(), until.Second(), until.Nanosecond(), loc)
+		}
+		e.Until = until
+	}
+
+	var (
+		weekdays    WeekdaySet
+		nthWeekdays = make(NthWeekdays)
+		monthDays   MonthDays
+	)
+	if byday, ok := parts["BYDAY"]; ok {
+		for _, d := range strings.Split(byday, ",") {
+			if len(d) < 2 {
+				return 0, fmt.Errorf("invalid BYDAY: %s", d)
+			}
+			wd, ok := strToWeekday[strings.ToLower(d[len(d)-2:])]
+			if !ok {
+				return 0, fmt.Errorf("invalid BYDAY: %s", d)
+			}
+			if len(d) == 2 {
+				weekdays = weekdays.Add(wd)
+				continue
+			}
+			n, err := strconv.Atoi(d[:len(d)-2])
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return 0, fmt.Errorf("invalid BYDAY: %s", d)
+			}
+			nthWeekdays[NthWeekday{N: n, Weekday: wd}] = true
+		}
+	}
+	if bymonthday, ok := parts["BYMONTHDAY"]; ok {
+		var err error
+		monthDays, err = ParseMonthDays(strings.Split(bymonthday, ","))
+		if err != nil {
+			return 0, fmt.Errorf("invalid BYMONTHDAY: %w", err)
+		}
+	}
+
+	startDay := e.Start
+	switch freq := parts["FREQ"]; {
+	case monthDays != nil && (weekdays.Len() > 0 || len(nthWeekdays) > 0):
+		return 0, errors.New("combining BYDAY and BYMONTHDAY is not supported")
+	case weekdays.Len() > 0 && len(nthWeekdays) > 0:
+		return 0, errors.New("combining BYDAY with and without ordinals is not supported")
+	case len(nthWeekdays) > 0 && freq != "MONTHLY":
+		return 0, errors.New("BYDAY ordinals are only supported with FREQ=MONTHLY")
+	case freq == "DAILY" && weekdays.Len() == 0 && monthDays == nil:
+		e.Recurrence = &TODWindow{Start: start, End: end, Location: loc}
+	case freq == "DAILY" || freq == "WEEKLY" || freq == "MONTHLY":
+		switch {
+		case len(nthWeekdays) > 0:
+			e.Recurrence = &TODNthWeekdayWindow{Start: start, End: end, NthWeekdays: nthWeekdays, Location: loc}
+		case monthDays != nil:
+			if freq == "WEEKLY" {
+				return 0, errors.New("BYMONTHDAY is not supported with FREQ=WEEKLY")
+			}
+			e.Recurrence = &TODMonthWindow{Start: start, End: end, MonthDays: monthDays, Location: loc}
+		case weekdays.Len() > 0:
+			e.Recurrence = &TODWeekWindow{Start: start, End: end, Days: weekdays, Location: loc}
+		case freq == "WEEKLY":
+			e.Recurrence = &TODWeekWindow{Start: start, End: end, Days: NewWeekdaySet(startDay.Weekday()), Location: loc}
+		default:
+			e.Recurrence = &TODMonthWindow{Start: start, End: end, MonthDays: MonthDays{startDay.Day(): true}, Location: loc}
+		}
+	default:
+		return 0, fmt.Errorf("unsupported FREQ: %s", freq)
+	}
+
+	return count, nil
+}
+
+// parseICalendarTime parses a DATE-TIME property value. Floating times are
+// returned as wall clock times in UTC. DATE values, with or without a
+// VALUE=DATE parameter, describe all-day events and are rejected.
+func parseICalendarTime(p icalendarProperty) (t time.Time, loc *time.Location, floating bool, err error) {
+	if strings.EqualFold(p.params["VALUE"], "DATE") || len(p.value) == len(icalendarDateLayout) {
+		return time.Time{}, nil, false, errors.New("all-day events are not supported")
+	}
+
+	if strings.HasSuffix(p.value, "Z") {
+		t, err = time.Parse(icalendarUTCLayout, p.value)
+		return t, time.UTC, false, err
+	}
+
+	if tzid, ok := p.params["TZID"]; ok {
+		loc, err = time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, nil, false, fmt.Errorf("TZID: %w", err)
+		}
+		t, err = time.ParseInLocation(icalendarLocalLayout, p.value, loc)
+		return t, loc, false, err
+	}
+
+	t, err = time.Parse(icalendarLocalLayout, p.value)
+	return t, time.UTC, true, err
+}
+
+// parseICalendarDuration parses a DURATION value such as "PT1H30M" or "P1D".
+// Days and weeks are taken to be 24 hours and 7 days long.
+func parseICalendarDuration(s string) (time.Duration, error) {
+	invalidErr := errors.New("invalid format (expected PT1H30M): " + s)
+
+	v := strings.TrimPrefix(strings.ToUpper(s), "+")
+	if !strings.HasPrefix(v, "P") || len(v) < 3 {
+		return 0, invalidErr
+	}
+	v = v[1:]
+
+	var (
+		d      time.Duration
+		inTime bool
+		num    string
+	)
+	for _, c := range v {
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+			continue
+		case c == 'T' && num == "" && !inTime:
+			inTime = true
+			continue
+		}
+
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, invalidErr
+		}
+		num = ""
+
+		var unit time.Duration
+		switch {
+		case c == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case c == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case c == 'H' && inTime:
+			unit = time.Hour
+		case c == 'M' && inTime:
+			unit = time.Minute
+		case c == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, invalidErr
+		}
+		d += time.Duration(n) * unit
+	}
+	if num != "" {
+		return 0, invalidErr
+	}
+	return d, nil
+}
+
+// formatICalendarTime formats t as a DATE-TIME property value and its
+// parameters.
+func formatICalendarTime(t time.Time, loc *time.Location, floating bool) (params, value string) {
+	switch {
+	case floating:
+		return "", t.Format(icalendarLocalLayout)
+	case loc == time.UTC:
+		return "", t.UTC().Format(icalendarUTCLayout)
+	default:
+		return ";TZID=" + loc.String(), t.In(loc).Format(icalendarLocalLayout)
+	}
+}
+
+// formatICalendarDuration formats d as a DURATION value.
+func formatICalendarDuration(d time.Duration) string {
+	if d <= 0 {
+		return "PT0S"
+	}
+
+	v := "PT"
+	if h := d / time.Hour; h > 0 {
+		v += fmt.Sprintf("%dH", h)
+	}
+	if m := d % time.Hour / time.Minute; m > 0 {
+		v += fmt.Sprintf("%dM", m)
+	}
+	if s := d % time.Minute / time.Second; s > 0 {
+		v += fmt.Sprintf("%dS", s)
+	}
+	return v
+}
+
+// formatRRule returns the RRULE value (without UNTIL) for a recurrence, along
+// with its start, end and Duration.
+func formatRRule(w Window) (rrule string, start, end TOD, length time.Duration, err error) {
+	switch w := w.(type) {
+	case *TODWindow:
+		return "FREQ=DAILY", w.Start, w.End, w.Duration, nil
+
+	case *TODWeekWindow:
+		var days []string
+		for _, wd := range w.days().Days() {
+			days = append(days, weekdayToICalendar[wd])
+		}
+		if len(days) == 0 {
+			return "", TOD{}, TOD{}, 0, errors.New("window has no weekdays")
+		}
+		return "FREQ=WEEKLY;BYDAY=" + strings.Join(days, ","), w.Start, w.End, w.Duration, nil
+
+	case *TODMonthWindow:
+		var days []int
+		for d, ok := range w.MonthDays {
+			if ok {
+				days = append(days, d)
+			}
+		}
+		if len(days) == 0 {
+			return "", TOD{}, TOD{}, 0, errors.New("window has no days of month")
+		}
+		sort.Ints(days)
+		var s []string
+		for _, d := range days {
+			s = append(s, strconv.Itoa(d))
+		}
+		return "FREQ=MONTHLY;BYMONTHDAY=" + strings.Join(s, ","), w.Start, w.End, 0, nil
+
+	case *TODNthWeekdayWindow:
+		var nwds []NthWeekday
+		for nwd, ok := range w.NthWeekdays {
+			if ok {
+				nwds = append(nwds, nwd)
+			}
+		}
+		if len(nwds) == 0 {
+			return "", TOD{}, TOD{}, 0, errors.New("window has no weekdays of month")
+		}
+		sort.Slice(nwds, func(i, j int) bool {
+			if nwds[i].N != nwds[j].N {
+				return nwds[i].N < nwds[j].N
+			}
+			return nwds[i].Weekday < nwds[j].Weekday
+		})
+		var s []string
+		for _, nwd := range nwds {
+			s = append(s, strconv.Itoa(nwd.N)+weekdayToICalendar[nwd.Weekday])
+		}
+		return "FREQ=MONTHLY;BYDAY=" + strings.Join(s, ","), w.Start, w.End, 0, nil
+	}
+
+	return "", TOD{}, TOD{}, 0, fmt.Errorf("unsupported window type %T", w)
+}
+
+// setRecurrenceLength sets the Duration of recurrences that last for 24 hours
+// or longer. Only weekly recurrences support this.
+func setRecurrenceLength(w Window, length time.Duration) error {
+	if length < 24*time.Hour {
+		return nil
+	}
+	switch w := w.(type) {
+	case *TODWeekWindow:
+		w.Duration = length
+	default:
+		return fmt.Errorf("unsupported length of %v (must be less than 24h for daily and monthly events)", length)
+	}
+	return nil
+}
+
+// recurrenceLocation returns the Location of a recurrence.
+func recurrenceLocation(w Window) (*time.Location, error) {
+	switch w := w.(type) {
+	case *TODWindow:
+		return w.Location, nil
+	case *TODWeekWindow:
+		return w.Location, nil
+	case *TODMonthWindow:
+		return w.Location, nil
+	case *TODNthWeekdayWindow:
+		return w.Location, nil
+	}
+	return nil, fmt.Errorf("unsupported window type %T", w)
+}
+
+// naiveTime returns the wall clock time of t in UTC.
+func naiveTime(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
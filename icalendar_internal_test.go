@@ -0,0 +1,399 @@
+package timewindow
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseICalendar(t *testing.T) {
+	const header = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\n"
+	const footer = "END:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	cases := []struct {
+		name  string
+		event string
+
+		from        time.Time
+		to          time.Time
+		occurrences []Interval
+		err         string
+	}{
+		{
+			name:  "daily-overnight-duration",
+			event: "DTSTART:20210601T220000Z\r\nDURATION:PT4H\r\nRRULE:FREQ=DAILY\r\n",
+			from:  time.Date(2021, time.May, 31, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2021, time.June, 3, 0, 0, 0, 0, time.UTC),
+			occurrences: []Interval{
+				{
+					Start: time.Date(2021, time.June, 1, 22, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.June, 2, 2, 0, 0, 0, time.UTC),
+				},
+				{
+					Start: time.Date(2021, time.June, 2, 22, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.June, 3, 2, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:  "weekly-tzid-dtend-count-exdate",
+			event: "DTSTART;TZID=Europe/Berlin:20210601T020000\r\nDTEND;TZID=Europe/Berlin:20210601T040000\r\nRRULE:FREQ=WEEKLY;BYDAY=TU,SA;COUNT=4\r\nEXDATE;TZID=Europe/Berlin:20210605T020000\r\n",
+			from:  time.Date(2021, time.May, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC),
+			occurrences: []Interval{
+				{
+					Start: time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.June, 1, 2, 0, 0, 0, time.UTC),
+				},
+				{
+					Start: time.Date(2021, time.June, 8, 0, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.June, 8, 2, 0, 0, 0, time.UTC),
+				},
+				{
+					Start: time.Date(2021, time.June, 12, 0, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.June, 12, 2, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:  "dtstart-not-in-byday",
+			event: "DTSTART:20210607T100000Z\r\nDURATION:PT1H\r\nRRULE:FREQ=WEEKLY;BYDAY=TU;COUNT=2\r\n",
+			from:  time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC),
+			occurrences: []Interval{
+				{
+					Start: time.Date(2021, time.June, 7, 10, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.June, 7, 11, 0, 0, 0, time.UTC),
+				},
+				{
+					Start: time.Date(2021, time.June, 8, 10, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.June, 8, 11, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:  "dtstart-not-in-byday-excluded",
+			event: "DTSTART:20210607T100000Z\r\nDURATION:PT1H\r\nRRULE:FREQ=WEEKLY;BYDAY=TU;COUNT=2\r\nEXDATE:20210607T100000Z\r\n",
+			from:  time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC),
+			occurrences: []Interval{
+				{
+					Start: time.Date(2021, time.June, 8, 10, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.June, 8, 11, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:  "weekly-multi-day",
+			event: "DTSTART:20210604T220000Z\r\nDURATION:P2DT8H\r\nRRULE:FREQ=WEEKLY;COUNT=2\r\n",
+			from:  time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC),
+			occurrences: []Interval{
+				{
+					Start: time.Date(2021, time.June, 4, 22, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.June, 7, 6, 0, 0, 0, time.UTC),
+				},
+				{
+					Start: time.Date(2021, time.June, 11, 22, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.June, 14, 6, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:  "monthly-nth-weekday-until",
+			event: "DTSTART:20210608T220000Z\r\nDURATION:PT4H\r\nRRULE:FREQ=MONTHLY;BYDAY=2TU;UNTIL=20210713T220000Z\r\n",
+			from:  time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2021, time.December, 1, 0, 0, 0, 0, time.UTC),
+			occurrences: []Interval{
+				{
+					Start: time.Date(2021, time.June, 8, 22, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.June, 9, 2, 0, 0, 0, time.UTC),
+				},
+				{
+					Start: time.Date(2021, time.July, 13, 22, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.July, 14, 2, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:  "until-date",
+			event: "DTSTART:20210608T220000Z\r\nDURATION:PT4H\r\nRRULE:FREQ=MONTHLY;BYDAY=2TU;UNTIL=20210713\r\n",
+			from:  time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2021, time.December, 1, 0, 0, 0, 0, time.UTC),
+			occurrences: []Interval{
+				{
+					Start: time.Date(2021, time.June, 8, 22, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.June, 9, 2, 0, 0, 0, time.UTC),
+				},
+				{
+					Start: time.Date(2021, time.July, 13, 22, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.July, 14, 2, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:  "monthly-last-day",
+			event: "DTSTART:20210630T230000Z\r\nDURATION:PT30M\r\nRRULE:FREQ=MONTHLY;BYMONTHDAY=-1\r\n",
+			from:  time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2021, time.August, 1, 0, 0, 0, 0, time.UTC),
+			occurrences: []Interval{
+				{
+					Start: time.Date(2021, time.June, 30, 23, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.June, 30, 23, 30, 0, 0, time.UTC),
+				},
+				{
+					Start: time.Date(2021, time.July, 31, 23, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.July, 31, 23, 30, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:  "single-folded",
+			event: "DTSTART:20210601T100000Z\r\nDTEND:20210601T\r\n 120000Z\r\nBEGIN:VALARM\r\nTRIGGER:-PT15M\r\nDURATION:PT5M\r\nEND:VALARM\r\n",
+			from:  time.Date(2021, time.May, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC),
+			occurrences: []Interval{
+				{
+					Start: time.Date(2021, time.June, 1, 10, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:  "single-multi-day",
+			event: "DTSTART:20210604T220000Z\r\nDURATION:P2DT8H\r\n",
+			from:  time.Date(2021, time.May, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC),
+			occurrences: []Interval{
+				{
+					Start: time.Date(2021, time.June, 4, 22, 0, 0, 0, time.UTC),
+					End:   time.Date(2021, time.June, 7, 6, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:  "floating",
+			event: "DTSTART:20210601T220000\r\nDURATION:PT1H\r\nRRULE:FREQ=DAILY;COUNT=2\r\n",
+			from:  time.Date(2021, time.May, 1, 0, 0, 0, 0, newYork),
+			to:    time.Date(2021, time.July, 1, 0, 0, 0, 0, newYork),
+			occurrences: []Interval{
+				{
+					Start: time.Date(2021, time.June, 1, 22, 0, 0, 0, newYork),
+					End:   time.Date(2021, time.June, 1, 23, 0, 0, 0, newYork),
+				},
+				{
+					Start: time.Date(2021, time.June, 2, 22, 0, 0, 0, newYork),
+					End:   time.Date(2021, time.June, 2, 23, 0, 0, 0, newYork),
+				},
+			},
+		},
+		{
+			name:  "missing-dtstart",
+			event: "DURATION:PT1H\r\n",
+			err:   "event 1: missing DTSTART",
+		},
+		{
+			name:  "interval",
+			event: "DTSTART:20210601T220000Z\r\nDURATION:PT1H\r\nRRULE:FREQ=WEEKLY;INTERVAL=2\r\n",
+			err:   "event 1: RRULE: unsupported INTERVAL: 2",
+		},
+		{
+			name:  "count-too-large",
+			event: "DTSTART:20210601T220000Z\r\nDURATION:PT1H\r\nRRULE:FREQ=DAILY;COUNT=10001\r\n",
+			err:   "event 1: RRULE: unsupported COUNT: 10001 (must be at most 10000)",
+		},
+		{
+			name:  "yearly",
+			event: "DTSTART:20210601T220000Z\r\nDURATION:PT1H\r\nRRULE:FREQ=YEARLY\r\n",
+			err:   "event 1: RRULE: unsupported FREQ: YEARLY",
+		},
+		{
+			name:  "too-long",
+			event: "DTSTART:20210601T220000Z\r\nDURATION:P1D\r\nRRULE:FREQ=MONTHLY\r\n",
+			err:   "event 1: unsupported length of 24h0m0s (must be less than 24h for daily and monthly events)",
+		},
+		{
+			name:  "too-long-daily",
+			event: "DTSTART:20210601T220000Z\r\nDURATION:PT30H\r\nRRULE:FREQ=DAILY\r\n",
+			err:   "event 1: unsupported length of 30h0m0s (must be less than 24h for daily and monthly events)",
+		},
+		{
+			name:  "all-day",
+			event: "DTSTART;VALUE=DATE:20210601\r\n",
+			err:   "event 1: DTSTART: all-day events are not supported",
+		},
+		{
+			name:  "all-day-without-value",
+			event: "DTSTART:20210601\r\n",
+			err:   "event 1: DTSTART: all-day events are not supported",
+		},
+		{
+			name:  "all-day-end",
+			event: "DTSTART:20210601T220000Z\r\nDTEND:20210602\r\n",
+			err:   "event 1: DTEND: all-day events are not supported",
+		},
+		{
+			name:  "bad-duration",
+			event: "DTSTART:20210601T220000Z\r\nDURATION:PT1X\r\n",
+			err:   "event 1: DURATION: invalid format (expected PT1H30M): PT1X",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			events, err := ParseICalendar(strings.NewReader(header + c.event + footer))
+			if c.err != "" {
+				require.EqualError(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+			require.Equal(t, intervalsString(c.occurrences), intervalsString(events[0].Occurrences(c.from, c.to)))
+		})
+	}
+}
+
+func TestFormatICalendarRoundTrip(t *testing.T) {
+	start := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name   string
+		window Window
+		ics    string
+	}{
+		{
+			name:   "daily-overnight",
+			window: &TODWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 2, Minute: 30}, Location: time.UTC},
+			ics:    "DTSTART:20210601T220000Z\r\nDURATION:PT4H30M\r\nRRULE:FREQ=DAILY\r\n",
+		},
+		{
+			name: "weekly-location",
+			window: &TODWeekWindow{
+				Start:    TOD{Hour: 2},
+				End:      TOD{Hour: 4},
+				Weekdays: Weekdays{time.Tuesday: true, time.Saturday: true, time.Sunday: false},
+				Location: berlin,
+			},
+			ics: "DTSTART;TZID=Europe/Berlin:20210601T020000\r\nDURATION:PT2H\r\nRRULE:FREQ=WEEKLY;BYDAY=TU,SA\r\n",
+		},
+		{
+			name:   "weekly-floating",
+			window: &TODWeekWindow{Start: TOD{Hour: 9}, End: TOD{Hour: 9, Minute: 45}, Weekdays: Weekdays{time.Friday: true}},
+			ics:    "DTSTART:20210604T090000\r\nDURATION:PT45M\r\nRRULE:FREQ=WEEKLY;BYDAY=FR\r\n",
+		},
+		{
+			name:   "weekly-duration",
+			window: &TODWeekWindow{Start: TOD{Hour: 22}, Duration: 56 * time.Hour, Weekdays: Weekdays{time.Friday: true}, Location: time.UTC},
+			ics:    "DTSTART:20210604T220000Z\r\nDURATION:PT56H\r\nRRULE:FREQ=WEEKLY;BYDAY=FR\r\n",
+		},
+		{
+			name:   "seconds",
+			window: &TODWindow{Start: TOD{Hour: 23, Minute: 59, Second: 30}, End: TOD{Second: 45}, Location: time.UTC},
+			ics:    "DTSTART:20210601T235930Z\r\nDURATION:PT1M15S\r\nRRULE:FREQ=DAILY\r\n",
+		},
+		{
+			name:   "month-days",
+			window: &TODMonthWindow{Start: TOD{Hour: 1}, End: TOD{Hour: 3}, MonthDays: MonthDays{15: true, 1: true, -1: true}, Location: time.UTC},
+			ics:    "DTSTART:20210601T010000Z\r\nDURATION:PT2H\r\nRRULE:FREQ=MONTHLY;BYMONTHDAY=-1,1,15\r\n",
+		},
+		{
+			name:   "nth-weekdays",
+			window: &TODNthWeekdayWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 2}, NthWeekdays: NthWeekdays{{N: 2, Weekday: time.Tuesday}: true, {N: -1, Weekday: time.Friday}: true}, Location: time.UTC},
+			ics:    "DTSTART:20210608T220000Z\r\nDURATION:PT4H\r\nRRULE:FREQ=MONTHLY;BYDAY=-1FR,2TU\r\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ics, err := FormatICalendar(c.window, start)
+			require.NoError(t, err)
+			require.Contains(t, ics, c.ics)
+
+			events, err := ParseICalendar(strings.NewReader(ics))
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+
+			from, to := start.AddDate(0, 0, -7), start.AddDate(0, 3, 0)
+			var expected []Interval
+			for _, o := range c.window.Occurrences(start, to) {
+				if !o.Start.Before(start) {
+					expected = append(expected, o)
+				}
+			}
+			require.Equal(t, intervalsString(expected), intervalsString(events[0].Occurrences(from, to)))
+
+			again, err := FormatICalendar(events[0], start)
+			require.NoError(t, err)
+			require.Equal(t, withoutDTStamp(ics), withoutDTStamp(again))
+		})
+	}
+}
+
+func TestFormatICalendarEvent(t *testing.T) {
+	const ics = "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n" +
+		"DTSTART;TZID=America/New_York:20210601T220000\r\nDURATION:PT1H\r\n" +
+		"RRULE:FREQ=DAILY;UNTIL=20210610T020000Z\r\n" +
+		"EXDATE;TZID=America/New_York:20210603T220000,20210605T220000\r\n" +
+		"END:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	events, err := ParseICalendar(strings.NewReader(ics))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Len(t, events[0].Occurrences(time.Date(2021, time.June, 1, 0, 0, 0, 0, newYork), time.Date(2021, time.July, 1, 0, 0, 0, 0, newYork)), 7)
+
+	out, err := FormatICalendar(events[0], time.Time{})
+	require.NoError(t, err)
+	require.Contains(t, out, "RRULE:FREQ=DAILY;UNTIL=20210610T020000Z\r\n")
+	require.Contains(t, out, "EXDATE;TZID=America/New_York:20210603T220000,20210605T220000\r\n")
+}
+
+func TestFormatICalendarUnsupported(t *testing.T) {
+	_, err := FormatICalendar(&AbsoluteWindow{}, time.Time{})
+	require.EqualError(t, err, "unsupported window type *timewindow.AbsoluteWindow")
+
+	_, err = FormatICalendar(Union(&TODWindow{}), time.Time{})
+	require.EqualError(t, err, "unsupported window type *timewindow.UnionWindow")
+
+	_, err = FormatICalendar(&TODWeekWindow{Weekdays: Weekdays{time.Monday: false}, Location: time.UTC}, time.Time{})
+	require.EqualError(t, err, "window does not occur after 0001-01-01 00:00:00 +0000 UTC")
+
+	for _, loc := range []*time.Location{time.FixedZone("X", 3600), time.Local} {
+		_, err = FormatICalendar(&TODWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 2}, Location: loc}, time.Time{})
+		require.Contains(t, err.Error(), "is not an IANA time zone name")
+	}
+}
+
+func TestFormatICalendarDTStamp(t *testing.T) {
+	before := time.Now().UTC().Truncate(time.Second)
+	ics, err := FormatICalendar(&TODWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 2}, Location: time.UTC}, time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	i := strings.Index(ics, "DTSTAMP:")
+	require.NotEqual(t, -1, i)
+	stamp, err := time.Parse(icalendarUTCLayout, ics[i+len("DTSTAMP:"):i+len("DTSTAMP:")+len(icalendarUTCLayout)])
+	require.NoError(t, err)
+	require.False(t, stamp.Before(before))
+	require.False(t, stamp.After(time.Now()))
+}
+
+// withoutDTStamp removes the DTSTAMP line, which holds the time that the
+// calendar was written at.
+func withoutDTStamp(ics string) string {
+	var lines []string
+	for _, line := range strings.Split(ics, "\r\n") {
+		if !strings.HasPrefix(line, "DTSTAMP:") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\r\n")
+}
+
+func intervalsString(intervals []Interval) []string {
+	var s []string
+	for _, i := range intervals {
+		s = append(s, i.Start.UTC().String()+" - "+i.End.UTC().String())
+	}
+	return s
+}
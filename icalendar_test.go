@@ -0,0 +1,65 @@
+package timewindow_test
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/nstogner/timewindow"
+)
+
+func ExampleParseICalendar() {
+	const ics = `BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+DTSTART;TZID=Europe/Berlin:20210601T020000
+DURATION:PT2H
+RRULE:FREQ=WEEKLY;BYDAY=TU,SA
+EXDATE;TZID=Europe/Berlin:20210605T020000
+END:VEVENT
+END:VCALENDAR
+`
+
+	events, err := timewindow.ParseICalendar(strings.NewReader(ics))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	from := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	for _, o := range events[0].Occurrences(from, from.AddDate(0, 0, 10)) {
+		fmt.Println(o.Start.UTC().Format(time.RFC1123), "-", o.End.UTC().Format(time.RFC1123))
+	}
+	// Output:
+	// Tue, 01 Jun 2021 00:00:00 UTC - Tue, 01 Jun 2021 02:00:00 UTC
+	// Tue, 08 Jun 2021 00:00:00 UTC - Tue, 08 Jun 2021 02:00:00 UTC
+}
+
+func ExampleFormatICalendar() {
+	window, err := timewindow.ParseTODWeekWindowInLocation("22:00", "02:00", []string{"mon", "fri"}, "America/Chicago")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ics, err := timewindow.FormatICalendar(window, time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(ics), "\r\n") {
+		// DTSTAMP is the time that the calendar was written at.
+		if !strings.HasPrefix(line, "DTSTAMP:") {
+			fmt.Println(line)
+		}
+	}
+	// Output:
+	// BEGIN:VCALENDAR
+	// VERSION:2.0
+	// PRODID:-//nstogner//timewindow//EN
+	// BEGIN:VEVENT
+	// UID:d73831348914c6fa@timewindow
+	// DTSTART;TZID=America/Chicago:20210531T220000
+	// DURATION:PT4H
+	// RRULE:FREQ=WEEKLY;BYDAY=MO,FR
+	// END:VEVENT
+	// END:VCALENDAR
+}
@@ -0,0 +1,126 @@
+package timewindow
+
+import "time"
+
+// Intersection returns a window that is open whenever all of the given windows
+// are open.
+func Intersection(windows ...Window) *IntersectionWindow {
+	return &IntersectionWindow{Windows: windows}
+}
+
+// IntersectionWindow is open whenever all of its windows are open. Each
+// occurrence lasts from the latest start to the earliest end of the overlapping
+// occurrences of its windows. An IntersectionWindow without windows is never
+// open.
+type IntersectionWindow struct {
+	Windows []Window
+}
+
+var _ Window = &IntersectionWindow{}
+
+// WithinWindow returns true if within all of the windows. It also returns the
+// time until the next window.
+func (x *IntersectionWindow) WithinWindow(now time.Time) WindowResult {
+	return withinOccurrences(x, now)
+}
+
+// NextOccurrence returns the first overlap of the windows that starts after t.
+func (x *IntersectionWindow) NextOccurrence(t time.Time) (Interval, bool) {
+	if len(x.Windows) == 0 {
+		return Interval{}, false
+	}
+
+	p := t
+	for p.Sub(t) <= horizon {
+		overlap, open := x.overlap(p)
+		if open {
+			if overlap.Start.After(t) {
+				return overlap, true
+			}
+			p = overlap.End
+			continue
+		}
+
+		// Move to the latest of the next starts of the closed windows.
+		for _, w := range x.Windows {
+			if o, ok := w.PreviousOccurrence(p); ok && o.Contains(p) {
+				continue
+			}
+			o, ok := nextNonEmptyOccurrence(w, p)
+			if !ok {
+				return Interval{}, false
+			}
+			if o.Start.After(overlap.Start) {
+				overlap.Start = o.Start
+			}
+		}
+		p = overlap.Start
+	}
+	return Interval{}, false
+}
+
+// PreviousOccurrence returns the last overlap of the windows that starts at or
+// before t.
+func (x *IntersectionWindow) PreviousOccurrence(t time.Time) (Interval, bool) {
+	if len(x.Windows) == 0 {
+		return Interval{}, false
+	}
+
+	p := t
+	for t.Sub(p) <= horizon {
+		overlap, open := x.overlap(p)
+		if open {
+			return overlap, true
+		}
+
+		// Move to just before the earliest of the previous ends of the closed
+		// windows.
+		var end time.Time
+		for _, w := range x.Windows {
+			o, ok := previousNonEmptyOccurrence(w, p)
+			if !ok {
+				return Interval{}, false
+			}
+			if o.Contains(p) {
+				continue
+			}
+			if end.IsZero() || o.End.Before(end) {
+				end = o.End
+			}
+		}
+		p = end.Add(-1)
+	}
+	return Interval{}, false
+}
+
+// Occurrences returns the overlaps of the windows that overlap the range from
+// from (inclusive) to to (exclusive).
+func (x *IntersectionWindow) Occurrences(from, to time.Time) []Interval {
+	return occurrences(x, from, to)
+}
+
+// Iterate returns an iterator over the overlaps of the windows, beginning with
+// the first one that ends after from.
+func (x *IntersectionWindow) Iterate(from time.Time) *OccurrenceIterator {
+	return newOccurrenceIterator(x, from)
+}
+
+// overlap returns the overlap of the occurrences of all windows that contain t.
+// It returns false if any of the windows is closed at t, in which case the
+// returned interval is empty and starts at t.
+func (x *IntersectionWindow) overlap(t time.Time) (Interval, bool) {
+	var overlap Interval
+	for i, w := range x.Windows {
+		o, ok := w.PreviousOccurrence(t)
+		if !ok || !o.Contains(t) {
+			return Interval{Start: t, End: t}, false
+		}
+		if i == 0 || o.Start.After(overlap.Start) {
+			overlap.Start = o.Start
+		}
+		if i == 0 || o.End.Before(overlap.End) {
+			overlap.End = o.End
+		}
+	}
+	return overlap, true
+}
@@ -0,0 +1,199 @@
+package timewindow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntersectionWindow(t *testing.T) {
+	team := &TODWindow{Start: TOD{Hour: 20}, End: TOD{Hour: 4}}
+	platform := &TODWeekWindow{
+		Start: TOD{Hour: 22},
+		End:   TOD{Hour: 6},
+		Weekdays: Weekdays{
+			time.Monday:    true,
+			time.Tuesday:   true,
+			time.Wednesday: true,
+			time.Thursday:  true,
+			time.Friday:    true,
+		},
+	}
+
+	cases := []struct {
+		name string
+
+		window *IntersectionWindow
+		now    time.Time
+
+		result   WindowResult
+		previous Interval
+		next     Interval
+	}{
+		{
+			name:   "within-both",
+			window: Intersection(team, platform),
+			now:    time.Date(2000, time.January, 3, 23, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 23 * time.Hour,
+				TTEnd:   5 * time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2000, time.January, 3, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 4, 4, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2000, time.January, 4, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 5, 4, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "within-one",
+			window: Intersection(team, platform),
+			now:    time.Date(2000, time.January, 3, 21, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(1999, time.December, 31, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 4, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2000, time.January, 3, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 4, 4, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "weekend",
+			window: Intersection(team, platform),
+			now:    time.Date(2000, time.January, 1, 23, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 47 * time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(1999, time.December, 31, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 4, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2000, time.January, 3, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 4, 4, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "three-windows",
+			window: Intersection(
+				&TODWindow{Start: TOD{Hour: 8}, End: TOD{Hour: 18}},
+				&TODWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 20}},
+				&TODWeekWindow{Start: TOD{Hour: 6}, End: TOD{Hour: 12}, Weekdays: Weekdays{time.Sunday: true}},
+			),
+			now: time.Date(2000, time.January, 2, 11, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 7*24*time.Hour - time.Hour,
+				TTEnd:   time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2000, time.January, 2, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 2, 12, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2000, time.January, 9, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 9, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "with-union",
+			window: Intersection(
+				&TODWindow{Start: TOD{Hour: 9}, End: TOD{Hour: 17}},
+				Union(
+					&TODWindow{Start: TOD{Hour: 8}, End: TOD{Hour: 10}},
+					&TODWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 12}},
+				),
+			),
+			now: time.Date(2000, time.January, 1, 9, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 0,
+				TTEnd:   3 * time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2000, time.January, 1, 9, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2000, time.January, 2, 9, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 2, 12, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result := c.window.WithinWindow(c.now)
+			require.Equal(t, c.result.Within, result.Within)
+			require.Equal(t, c.result.TTStart.String(), result.TTStart.String())
+			require.Equal(t, c.result.TTEnd.String(), result.TTEnd.String())
+
+			previous, ok := c.window.PreviousOccurrence(c.now)
+			require.True(t, ok)
+			require.Equal(t, c.previous.Start.String(), previous.Start.String())
+			require.Equal(t, c.previous.End.String(), previous.End.String())
+
+			next, ok := c.window.NextOccurrence(c.now)
+			require.True(t, ok)
+			require.Equal(t, c.next.Start.String(), next.Start.String())
+			require.Equal(t, c.next.End.String(), next.End.String())
+		})
+	}
+}
+
+func TestIntersectionWindowNever(t *testing.T) {
+	now := time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	for name, window := range map[string]*IntersectionWindow{
+		"no-windows": Intersection(),
+		"disjoint": Intersection(
+			&TODWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 12}},
+			&TODWindow{Start: TOD{Hour: 13}, End: TOD{Hour: 14}},
+		),
+	} {
+		t.Run(name, func(t *testing.T) {
+			result := window.WithinWindow(now)
+			require.False(t, result.Within)
+			require.Equal(t, Never, result.TTStart)
+
+			_, ok := window.NextOccurrence(now)
+			require.False(t, ok)
+			_, ok = window.PreviousOccurrence(now)
+			require.False(t, ok)
+		})
+	}
+}
+
+func BenchmarkIntersectionWindowWithinWindow(b *testing.B) {
+	weekNights := &TODWeekWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 6}, Days: NewWeekdaySet(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)}
+	now := time.Date(2021, time.June, 8, 23, 0, 0, 0, time.UTC) // Tuesday
+
+	cases := map[string]*IntersectionWindow{
+		"within": Intersection(&TODWindow{Start: TOD{Hour: 20}, End: TOD{Hour: 4}}, weekNights),
+		// Disjoint windows never overlap, so every search covers the horizon.
+		"disjoint": Intersection(&TODWindow{Start: TOD{Hour: 8}, End: TOD{Hour: 12}}, weekNights),
+	}
+	for name, x := range cases {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				x.WithinWindow(now)
+			}
+		})
+	}
+}
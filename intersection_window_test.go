@@ -0,0 +1,29 @@
+package timewindow_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nstogner/timewindow"
+)
+
+func ExampleIntersectionWindow() {
+	team, err := timewindow.ParseTODWindow("20:00", "04:00")
+	if err != nil {
+		log.Fatal(err)
+	}
+	platform, err := timewindow.ParseTODWeekWindow("22:00", "06:00", []string{"Mon", "Tue", "Wed", "Thu", "Fri"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	window := timewindow.Intersection(team, platform)
+
+	now := time.Date(2000, time.January, 3, 21, 0, 0, 0, time.UTC)
+	result := window.WithinWindow(now)
+	fmt.Println("within: ", result.Within)
+	fmt.Println("untilStart: ", result.TTStart)
+	// Output:
+	// within:  false
+	// untilStart:  1h0m0s
+}
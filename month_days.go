@@ -0,0 +1,68 @@
+package timewindow
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseMonthDays parses days of the month such as "1", "15" or "-1". Negative
+// days count back from the end of the month and "last" is the same as "-1".
+func ParseMonthDays(daysOfMonth []string) (MonthDays, error) {
+	mds := make(MonthDays)
+	for _, d := range daysOfMonth {
+		s := strings.TrimSpace(strings.ToLower(d))
+		if s == "last" {
+			mds[-1] = true
+			continue
+		}
+
+		md, err := strconv.Atoi(s)
+		if err != nil || md == 0 || md < -31 || md > 31 {
+			return nil, fmt.Errorf("unrecognized day of month: %s", d)
+		}
+		mds[md] = true
+	}
+	return mds, nil
+}
+
+// MonthDays is a set of days of the month. Days from 1 to 31 count from the
+// start of the month and days from -1 to -31 count back from the end of the
+// month, so -1 is the last day. Days that do not exist in a month (such as 31
+// in April) are skipped for that month.
+type MonthDays map[int]bool
+
+// Matches returns true if the calendar date of t is one of the days.
+func (m MonthDays) Matches(t time.Time) bool {
+	last := daysIn(t.Month(), t.Year())
+	return m[t.Day()] || m[t.Day()-last-1]
+}
+
+// days returns the days counted from the start of the month in ascending
+// order, followed by the days counted from the end of the month starting with
+// the last day.
+func (m MonthDays) days() []int {
+	days := make([]int, 0, len(m))
+	for d, ok := range m {
+		if ok {
+			days = append(days, d)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool {
+		if (days[i] > 0) != (days[j] > 0) {
+			return days[i] > 0
+		}
+		if days[i] > 0 {
+			return days[i] < days[j]
+		}
+		return days[i] > days[j]
+	})
+	return days
+}
+
+// daysIn returns the number of days in the month.
+func daysIn(month time.Month, year int) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,134 @@
+package timewindow
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonthDays(t *testing.T) {
+	cases := []struct {
+		name string
+
+		monthDays MonthDays
+		day       time.Time
+
+		matches bool
+	}{
+		{
+			name:      "first",
+			monthDays: MonthDays{1: true, 15: true},
+			day:       time.Date(2021, time.February, 1, 0, 0, 0, 0, time.UTC),
+			matches:   true,
+		},
+		{
+			name:      "no-match",
+			monthDays: MonthDays{1: true, 15: true},
+			day:       time.Date(2021, time.February, 2, 0, 0, 0, 0, time.UTC),
+			matches:   false,
+		},
+		{
+			name:      "last-of-short-month",
+			monthDays: MonthDays{-1: true},
+			day:       time.Date(2021, time.February, 28, 0, 0, 0, 0, time.UTC),
+			matches:   true,
+		},
+		{
+			name:      "last-of-leap-month",
+			monthDays: MonthDays{-1: true},
+			day:       time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC),
+			matches:   true,
+		},
+		{
+			name:      "not-last-of-leap-month",
+			monthDays: MonthDays{-1: true},
+			day:       time.Date(2020, time.February, 28, 0, 0, 0, 0, time.UTC),
+			matches:   false,
+		},
+		{
+			name:      "second-to-last",
+			monthDays: MonthDays{-2: true},
+			day:       time.Date(2021, time.April, 29, 0, 0, 0, 0, time.UTC),
+			matches:   true,
+		},
+		{
+			name:      "31st-in-30-day-month",
+			monthDays: MonthDays{31: true},
+			day:       time.Date(2021, time.April, 30, 0, 0, 0, 0, time.UTC),
+			matches:   false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.matches, c.monthDays.Matches(c.day))
+		})
+	}
+}
+
+func TestParseMonthDaysHappyPath(t *testing.T) {
+	cases := []struct {
+		s []string
+		m MonthDays
+	}{
+		{
+			s: []string{"1", "15"},
+			m: MonthDays{1: true, 15: true},
+		},
+		{
+			s: []string{"Last", " -2 ", "31"},
+			m: MonthDays{-1: true, -2: true, 31: true},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(strings.Join(c.s, ","), func(t *testing.T) {
+			m, err := ParseMonthDays(c.s)
+			require.NoError(t, err)
+			require.Equal(t, c.m, m)
+		})
+	}
+}
+
+func TestParseMonthDaysSadPath(t *testing.T) {
+	cases := []struct {
+		name    string
+		s       []string
+		errText string
+	}{
+		{
+			name:    "empty",
+			s:       []string{""},
+			errText: "unrecognized",
+		},
+		{
+			name:    "zero",
+			s:       []string{"0"},
+			errText: "unrecognized day of month: 0",
+		},
+		{
+			name:    "too-large",
+			s:       []string{"32"},
+			errText: "32",
+		},
+		{
+			name:    "too-small",
+			s:       []string{"-32"},
+			errText: "-32",
+		},
+		{
+			name:    "not-a-day",
+			s:       []string{"first"},
+			errText: "first",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ParseMonthDays(c.s)
+			require.Contains(t, err.Error(), c.errText)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package timewindow
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var strToNth = map[string]int{
+	"1st":   1,
+	"first": 1,
+
+	"2nd":    2,
+	"second": 2,
+
+	"3rd":   3,
+	"third": 3,
+
+	"4th":    4,
+	"fourth": 4,
+
+	"5th":   5,
+	"fifth": 5,
+
+	"last":     -1,
+	"2nd-last": -2,
+	"3rd-last": -3,
+	"4th-last": -4,
+	"5th-last": -5,
+}
+
+// nthToStr are the ordinals that NthWeekday.String writes.
+var nthToStr = map[int]string{
+	1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 5: "5th",
+	-1: "last", -2: "2nd-last", -3: "3rd-last", -4: "4th-last", -5: "5th-last",
+}
+
+// ParseNthWeekdays parses weekdays of the month such as "2nd tue", "first
+// monday", "last fri" or "2nd-last fri".
+func ParseNthWeekdays(nthWeekdays []string) (NthWeekdays, error) {
+	nwds := make(NthWeekdays)
+	for _, s := range nthWeekdays {
+		fields := strings.Fields(strings.ToLower(s))
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid format (expected 2nd tue): %s", s)
+		}
+
+		n, ok := strToNth[fields[0]]
+		if !ok {
+			return nil, fmt.Errorf("unrecognized ordinal: %s", fields[0])
+		}
+		wd, ok := strToWeekday[fields[1]]
+		if !ok {
+			return nil, fmt.Errorf("unrecognized weekday: %s", fields[1])
+		}
+		nwds[NthWeekday{N: n, Weekday: wd}] = true
+	}
+	return nwds, nil
+}
+
+// NthWeekday is the Nth occurrence of a weekday in a month. N ranges from 1 to
+// 5 to count from the start of the month, and from -1 to -5 to count back from
+// the end of the month, so {-1, time.Friday} is the last Friday.
+type NthWeekday struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// String returns the weekday in the form accepted by ParseNthWeekdays, such
+// as "2nd Tue" or "last Fri".
+func (n NthWeekday) String() string {
+	nth, ok := nthToStr[n.N]
+	if !ok {
+		nth = strconv.Itoa(n.N)
+	}
+	return nth + " " + n.Weekday.String()[:3]
+}
+
+// NthWeekdays is a set of weekdays of the month. Months without a matching day
+// (such as a 5th Monday) are skipped.
+type NthWeekdays map[NthWeekday]bool
+
+// Matches returns true if the calendar date of t is one of the weekdays.
+func (n NthWeekdays) Matches(t time.Time) bool {
+	fromStart := (t.Day()-1)/7 + 1
+	fromEnd := (daysIn(t.Month(), t.Year())-t.Day())/7 + 1
+	return n[NthWeekday{N: fromStart, Weekday: t.Weekday()}] || n[NthWeekday{N: -fromEnd, Weekday: t.Weekday()}]
+}
+
+// names returns the weekdays formatted with NthWeekday.String, ordered by
+// weekday and then with the ones counted from the start of the month first.
+// "last" sorts before "2nd-last".
+func (n NthWeekdays) names() []string {
+	nwds := make([]NthWeekday, 0, len(n))
+	for nwd, ok := range n {
+		if ok {
+			nwds = append(nwds, nwd)
+		}
+	}
+	sort.Slice(nwds, func(i, j int) bool {
+		a, b := nwds[i], nwds[j]
+		if a.Weekday != b.Weekday {
+			return a.Weekday < b.Weekday
+		}
+		if (a.N > 0) != (b.N > 0) {
+			return a.N > 0
+		}
+		if a.N > 0 {
+			return a.N < b.N
+		}
+		return a.N > b.N
+	})
+
+	names := make([]string, len(nwds))
+	for i, nwd := range nwds {
+		names[i] = nwd.String()
+	}
+	return names
+}
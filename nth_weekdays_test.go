@@ -0,0 +1,123 @@
+package timewindow
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNthWeekdays(t *testing.T) {
+	cases := []struct {
+		name string
+
+		nthWeekdays NthWeekdays
+		day         time.Time
+
+		matches bool
+	}{
+		{
+			name:        "second-tuesday",
+			nthWeekdays: NthWeekdays{{N: 2, Weekday: time.Tuesday}: true},
+			day:         time.Date(2021, time.June, 8, 0, 0, 0, 0, time.UTC),
+			matches:     true,
+		},
+		{
+			name:        "first-tuesday",
+			nthWeekdays: NthWeekdays{{N: 2, Weekday: time.Tuesday}: true},
+			day:         time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC),
+			matches:     false,
+		},
+		{
+			name:        "second-wednesday",
+			nthWeekdays: NthWeekdays{{N: 2, Weekday: time.Tuesday}: true},
+			day:         time.Date(2021, time.June, 9, 0, 0, 0, 0, time.UTC),
+			matches:     false,
+		},
+		{
+			name:        "last-friday",
+			nthWeekdays: NthWeekdays{{N: -1, Weekday: time.Friday}: true},
+			day:         time.Date(2021, time.April, 30, 0, 0, 0, 0, time.UTC),
+			matches:     true,
+		},
+		{
+			name:        "second-to-last-friday",
+			nthWeekdays: NthWeekdays{{N: -1, Weekday: time.Friday}: true},
+			day:         time.Date(2021, time.April, 23, 0, 0, 0, 0, time.UTC),
+			matches:     false,
+		},
+		{
+			name:        "fifth-friday-is-last",
+			nthWeekdays: NthWeekdays{{N: 5, Weekday: time.Friday}: true},
+			day:         time.Date(2021, time.April, 30, 0, 0, 0, 0, time.UTC),
+			matches:     true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.matches, c.nthWeekdays.Matches(c.day))
+		})
+	}
+}
+
+func TestParseNthWeekdaysHappyPath(t *testing.T) {
+	cases := []struct {
+		s []string
+		n NthWeekdays
+	}{
+		{
+			s: []string{"2nd tue", "Last Fri"},
+			n: NthWeekdays{{N: 2, Weekday: time.Tuesday}: true, {N: -1, Weekday: time.Friday}: true},
+		},
+		{
+			s: []string{"first  monday", "5th SU"},
+			n: NthWeekdays{{N: 1, Weekday: time.Monday}: true, {N: 5, Weekday: time.Sunday}: true},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(strings.Join(c.s, ","), func(t *testing.T) {
+			n, err := ParseNthWeekdays(c.s)
+			require.NoError(t, err)
+			require.Equal(t, c.n, n)
+		})
+	}
+}
+
+func TestParseNthWeekdaysSadPath(t *testing.T) {
+	cases := []struct {
+		name    string
+		s       []string
+		errText string
+	}{
+		{
+			name:    "empty",
+			s:       []string{""},
+			errText: "invalid format",
+		},
+		{
+			name:    "missing-ordinal",
+			s:       []string{"tue"},
+			errText: "invalid format",
+		},
+		{
+			name:    "bad-ordinal",
+			s:       []string{"6th tue"},
+			errText: "unrecognized ordinal: 6th",
+		},
+		{
+			name:    "bad-weekday",
+			s:       []string{"2nd day"},
+			errText: "unrecognized weekday: day",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ParseNthWeekdays(c.s)
+			require.Contains(t, err.Error(), c.errText)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package timewindow
+
+import "time"
+
+// horizon bounds how far window computations search for occurrences that are
+// not guaranteed to exist, such as the end of a period that is covered by
+// back-to-back occurrences.
+const horizon = 366 * 24 * time.Hour
+
+// OccurrenceIterator lazily walks the occurrences of a window in order of
+// their start time.
+type OccurrenceIterator struct {
+	w       Window
+	from    time.Time
+	last    Interval
+	started bool
+	done    bool
+}
+
+func newOccurrenceIterator(w Window, from time.Time) *OccurrenceIterator {
+	return &OccurrenceIterator{w: w, from: from}
+}
+
+// Next returns the next occurrence. It returns false once the window does not
+// occur again.
+func (it *OccurrenceIterator) Next() (Interval, bool) {
+	if it.done {
+		return Interval{}, false
+	}
+
+	var (
+		o  Interval
+		ok bool
+	)
+	if !it.started {
+		// The first occurrence may have started before from.
+		it.started = true
+		o, ok = it.w.PreviousOccurrence(it.from)
+		if !ok || !o.End.After(it.from) {
+			o, ok = it.w.NextOccurrence(it.from)
+		}
+	} else {
+		o, ok = it.w.NextOccurrence(it.last.Start)
+	}
+
+	if !ok {
+		it.done = true
+		return Interval{}, false
+	}
+	it.last = o
+	return o, true
+}
+
+// occurrences returns the occurrences of w that overlap the range from from
+// (inclusive) to to (exclusive). Occurrences of zero length are left out.
+func occurrences(w Window, from, to time.Time) []Interval {
+	var result []Interval
+	it := newOccurrenceIterator(w, from)
+	for {
+		o, ok := it.Next()
+		if !ok || !o.Start.Before(to) {
+			return result
+		}
+		if o.End.After(o.Start) {
+			result = append(result, o)
+		}
+	}
+}
+
+// previousStartTime returns the start of the last occurrence of w that started
+// at or before now.
+func previousStartTime(w Window, now time.Time) (time.Time, bool) {
+	o, ok := w.PreviousOccurrence(now)
+	return o.Start, ok
+}
+
+// previousEndTime returns the end of the last occurrence of w that ended at or
+// before now.
+func previousEndTime(w Window, now time.Time) (time.Time, bool) {
+	o, ok := w.PreviousOccurrence(now)
+	return previousEndTimeFrom(w, now, o, ok)
+}
+
+// previousEndTimeFrom is like previousEndTime but starts from o and ok, the
+// results of w.PreviousOccurrence(now). Occurrences may overlap, so it steps
+// back past every occurrence that is still open at now, for at most the
+// horizon.
+func previousEndTimeFrom(w Window, now time.Time, o Interval, ok bool) (time.Time, bool) {
+	for ok && o.End.After(now) {
+		if now.Sub(o.Start) > horizon {
+			return time.Time{}, false
+		}
+		o, ok = w.PreviousOccurrence(o.Start.Add(-1))
+	}
+	return o.End, ok
+}
+
+// withTimeSince fills in the time since the previous occurrence of w started
+// and ended. The fields are set to Never if there is no such occurrence.
+func withTimeSince(r WindowResult, w Window, now time.Time) WindowResult {
+	prev, ok := w.PreviousOccurrence(now)
+	return withTimeSinceFrom(r, w, now, prev, ok)
+}
+
+// withTimeSinceFrom is like withTimeSince but reuses prev, the result of
+// w.PreviousOccurrence(now), which can be expensive for composite windows.
+func withTimeSinceFrom(r WindowResult, w Window, now time.Time, prev Interval, ok bool) WindowResult {
+	r.TSStart, r.TSEnd = Never, Never
+	if !ok {
+		return r
+	}
+	r.TSStart = now.Sub(prev.Start)
+	if end, ok := previousEndTimeFrom(w, now, prev, ok); ok {
+		r.TSEnd = now.Sub(end)
+	}
+	return r
+}
+
+// nextNonEmptyOccurrence returns the first occurrence of w with a non-zero
+// length that starts after t.
+func nextNonEmptyOccurrence(w Window, t time.Time) (Interval, bool) {
+	o, ok := w.NextOccurrence(t)
+	for ok && !o.End.After(o.Start) {
+		if o.Start.Sub(t) > horizon {
+			return Interval{}, false
+		}
+		o, ok = w.NextOccurrence(o.Start)
+	}
+	return o, ok
+}
+
+// previousNonEmptyOccurrence returns the last occurrence of w with a non-zero
+// length that starts at or before t.
+func previousNonEmptyOccurrence(w Window, t time.Time) (Interval, bool) {
+	o, ok := w.PreviousOccurrence(t)
+	for ok && !o.End.After(o.Start) {
+		if t.Sub(o.Start) > horizon {
+			return Interval{}, false
+		}
+		o, ok = w.PreviousOccurrence(o.Start.Add(-1))
+	}
+	return o, ok
+}
+
+// withinOccurrences computes the WindowResult for w from its occurrences. The
+// previous occurrence is looked up once, since composite windows may search
+// up to the horizon to find it.
+func withinOccurrences(w Window, now time.Time) WindowResult {
+	var r WindowResult
+	cur, curOK := w.PreviousOccurrence(now)
+	if curOK && cur.Contains(now) {
+		following, ok := w.NextOccurrence(now)
+		r = WithinWindow(now, cur.Start, cur.End, following.Start)
+		if !ok && r.TTStart != 0 {
+			r.TTStart = Never
+		}
+	} else if next, ok := w.NextOccurrence(now); ok {
+		r = WithinWindow(now, next.Start, next.End, next.Start)
+	} else {
+		r = WindowResult{TTStart: Never}
+	}
+	return withTimeSinceFrom(r, w, now, cur, curOK)
+}
@@ -0,0 +1,186 @@
+package timewindow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOccurrences(t *testing.T) {
+	cases := []struct {
+		name string
+
+		window Window
+		from   time.Time
+		to     time.Time
+
+		occurrences []Interval
+	}{
+		{
+			name: "daily",
+			window: &TODWindow{
+				Start: TOD{Hour: 10, Minute: 0},
+				End:   TOD{Hour: 20, Minute: 0},
+			},
+			from: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2000, time.January, 3, 0, 0, 0, 0, time.UTC),
+
+			occurrences: []Interval{
+				{
+					Start: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+					End:   time.Date(2000, time.January, 1, 20, 0, 0, 0, time.UTC),
+				},
+				{
+					Start: time.Date(2000, time.January, 2, 10, 0, 0, 0, time.UTC),
+					End:   time.Date(2000, time.January, 2, 20, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "daily-from-within",
+			window: &TODWindow{
+				Start: TOD{Hour: 10, Minute: 0},
+				End:   TOD{Hour: 20, Minute: 0},
+			},
+			from: time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC),
+			to:   time.Date(2000, time.January, 2, 10, 0, 0, 0, time.UTC),
+
+			occurrences: []Interval{
+				{
+					Start: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+					End:   time.Date(2000, time.January, 1, 20, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "overnight",
+			window: &TODWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+			},
+			from: time.Date(2000, time.January, 2, 1, 0, 0, 0, time.UTC),
+			to:   time.Date(2000, time.January, 3, 23, 0, 0, 0, time.UTC),
+
+			occurrences: []Interval{
+				{
+					Start: time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+					End:   time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+				},
+				{
+					Start: time.Date(2000, time.January, 2, 22, 0, 0, 0, time.UTC),
+					End:   time.Date(2000, time.January, 3, 2, 0, 0, 0, time.UTC),
+				},
+				{
+					Start: time.Date(2000, time.January, 3, 22, 0, 0, 0, time.UTC),
+					End:   time.Date(2000, time.January, 4, 2, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "weekdays",
+			window: &TODWeekWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+				Weekdays: Weekdays{
+					time.Tuesday:  true,
+					time.Saturday: true,
+				},
+			},
+			from: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2000, time.January, 15, 0, 0, 0, 0, time.UTC),
+
+			occurrences: []Interval{
+				{
+					Start: time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+					End:   time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+				},
+				{
+					Start: time.Date(2000, time.January, 4, 22, 0, 0, 0, time.UTC),
+					End:   time.Date(2000, time.January, 5, 2, 0, 0, 0, time.UTC),
+				},
+				{
+					Start: time.Date(2000, time.January, 8, 22, 0, 0, 0, time.UTC),
+					End:   time.Date(2000, time.January, 9, 2, 0, 0, 0, time.UTC),
+				},
+				{
+					Start: time.Date(2000, time.January, 11, 22, 0, 0, 0, time.UTC),
+					End:   time.Date(2000, time.January, 12, 2, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "no-weekdays",
+			window: &TODWeekWindow{
+				Start:    TOD{Hour: 10, Minute: 0},
+				End:      TOD{Hour: 20, Minute: 0},
+				Weekdays: Weekdays{},
+			},
+			from: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2000, time.January, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "zero-length",
+			window: &TODWindow{
+				Start: TOD{Hour: 10, Minute: 0},
+				End:   TOD{Hour: 10, Minute: 0},
+			},
+			from: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2000, time.January, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "skips-empty-dst-day",
+			window: &TODWindow{
+				Start: TOD{Hour: 2, Minute: 30},
+				End:   TOD{Hour: 3, Minute: 15},
+			},
+			from: time.Date(2021, time.March, 13, 0, 0, 0, 0, newYork),
+			to:   time.Date(2021, time.March, 16, 0, 0, 0, 0, newYork),
+
+			occurrences: []Interval{
+				{
+					Start: time.Date(2021, time.March, 13, 2, 30, 0, 0, newYork),
+					End:   time.Date(2021, time.March, 13, 3, 15, 0, 0, newYork),
+				},
+				{
+					Start: time.Date(2021, time.March, 15, 2, 30, 0, 0, newYork),
+					End:   time.Date(2021, time.March, 15, 3, 15, 0, 0, newYork),
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			occurrences := c.window.Occurrences(c.from, c.to)
+			require.Len(t, occurrences, len(c.occurrences))
+			for i := range c.occurrences {
+				require.Equal(t, c.occurrences[i].Start.String(), occurrences[i].Start.String())
+				require.Equal(t, c.occurrences[i].End.String(), occurrences[i].End.String())
+			}
+		})
+	}
+}
+
+func TestOccurrenceIterator(t *testing.T) {
+	window := &TODWeekWindow{
+		Start: TOD{Hour: 10, Minute: 0},
+		End:   TOD{Hour: 12, Minute: 0},
+		Weekdays: Weekdays{
+			time.Monday: true,
+		},
+	}
+
+	it := window.Iterate(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	for i := 0; i < 52; i++ {
+		o, ok := it.Next()
+		require.True(t, ok)
+		require.Equal(t, time.Monday, o.Start.Weekday())
+		require.Equal(t, time.Date(2000, time.January, 3+7*i, 10, 0, 0, 0, time.UTC).String(), o.Start.String())
+		require.Equal(t, 2*time.Hour, o.End.Sub(o.Start))
+	}
+
+	empty := &TODWeekWindow{Weekdays: Weekdays{}}
+	_, ok := empty.Iterate(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)).Next()
+	require.False(t, ok)
+}
@@ -5,18 +5,30 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // TOD is Time Of Day.
 type TOD struct {
-	Hour   int
-	Minute int
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
 }
 
+// EndOfDay is the end of a day, written as "24:00". It is midnight at the
+// start of the following day.
+var EndOfDay = TOD{Hour: 24}
+
+var errEndOfDayStart = errors.New("24:00 is only valid as the end of a window")
+
+// ParseTOD parses a time of day in the form "12:34" or "12:34:56". The seconds
+// may have a fraction of up to nine digits, as in "12:34:56.789". "24:00" is
+// parsed as EndOfDay.
 func ParseTOD(s string) (TOD, error) {
-	invalidErr := errors.New("invalid format (expected 12:34): " + s)
+	invalidErr := errors.New("invalid format (expected 12:34 or 12:34:56): " + s)
 	split := strings.Split(s, ":")
-	if len(split) != 2 {
+	if len(split) != 2 && len(split) != 3 {
 		return TOD{}, invalidErr
 	}
 
@@ -29,12 +41,256 @@ func ParseTOD(s string) (TOD, error) {
 		return TOD{}, fmt.Errorf("%s: parsing minute: %w", invalidErr, err)
 	}
 
+	var second, nanosecond int
+	if len(split) == 3 {
+		sec, frac := split[2], ""
+		if i := strings.Index(sec, "."); i >= 0 {
+			sec, frac = sec[:i], sec[i+1:]
+		}
+
+		second, err = strconv.Atoi(sec)
+		if err != nil {
+			return TOD{}, fmt.Errorf("%s: parsing second: %w", invalidErr, err)
+		}
+		if len(split[2]) > len(sec) {
+			nanosecond, err = parseFraction(frac)
+			if err != nil {
+				return TOD{}, fmt.Errorf("%s: parsing fraction of second: %w", invalidErr, err)
+			}
+		}
+	}
+
+	if hour == 24 && minute == 0 && second == 0 && nanosecond == 0 {
+		return EndOfDay, nil
+	}
+	if hour == 24 {
+		return TOD{}, fmt.Errorf("invalid time after 24:00: %s", s)
+	}
 	if hour < 0 || hour > 23 {
 		return TOD{}, fmt.Errorf("invalid hour: %v", hour)
 	}
 	if minute < 0 || minute > 59 {
 		return TOD{}, fmt.Errorf("invalid minute: %v", minute)
 	}
+	if second < 0 || second > 59 {
+		return TOD{}, fmt.Errorf("invalid second: %v", second)
+	}
+
+	return TOD{Hour: hour, Minute: minute, Second: second, Nanosecond: nanosecond}, nil
+}
+
+// String returns the time of day in the form accepted by ParseTOD. Seconds
+// and the fraction of a second are only included if they are not zero.
+func (t TOD) String() string {
+	s := fmt.Sprintf("%02d:%02d", t.Hour, t.Minute)
+	if t.Second != 0 || t.Nanosecond != 0 {
+		s += fmt.Sprintf(":%02d", t.Second)
+	}
+	if t.Nanosecond != 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%09d", t.Nanosecond), "0")
+	}
+	return s
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t TOD) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseTOD.
+func (t *TOD) UnmarshalText(text []byte) error {
+	tod, err := ParseTOD(string(text))
+	if err != nil {
+		return err
+	}
+	*t = tod
+	return nil
+}
 
-	return TOD{Hour: hour, Minute: minute}, nil
+// parseStartTOD is like ParseTOD but rejects EndOfDay, which cannot start a
+// window.
+func parseStartTOD(s string) (TOD, error) {
+	tod, err := ParseTOD(s)
+	if err != nil {
+		return TOD{}, err
+	}
+	if tod == EndOfDay {
+		return TOD{}, errEndOfDayStart
+	}
+	return tod, nil
+}
+
+// namedTODs are the named times of day accepted by ParseTODLenient.
+var namedTODs = map[string]TOD{
+	"midnight": {Hour: 0},
+	"noon":     {Hour: 12},
+}
+
+// ParseTODLenient parses a time of day in any of the forms that operators
+// commonly write:
+//
+//   - 24-hour clock as accepted by ParseTOD: "14:30", "14:30:15"
+//   - 12-hour clock: "2pm", "2:30 PM", "12:15:30 a.m."
+//   - military time: "1430", "930"
+//   - named times: "noon", "midnight"
+//
+// Errors name the form that the input was recognized as.
+func ParseTODLenient(s string) (TOD, error) {
+	v := strings.ToLower(strings.TrimSpace(s))
+
+	if tod, ok := namedTODs[v]; ok {
+		return tod, nil
+	}
+
+	if clock, pm, ok := cutMeridiem(v); ok {
+		tod, err := parse12HourTOD(clock, pm)
+		if err != nil {
+			return TOD{}, fmt.Errorf("12-hour clock: %w", err)
+		}
+		return tod, nil
+	}
+
+	if strings.Contains(v, ":") {
+		tod, err := ParseTOD(v)
+		if err != nil {
+			return TOD{}, fmt.Errorf("24-hour clock: %w", err)
+		}
+		return tod, nil
+	}
+
+	if (len(v) == 3 || len(v) == 4) && strings.Trim(v, "0123456789") == "" {
+		tod, err := ParseTOD(v[:len(v)-2] + ":" + v[len(v)-2:])
+		if err != nil {
+			return TOD{}, fmt.Errorf("military time: %w", err)
+		}
+		return tod, nil
+	}
+
+	return TOD{}, fmt.Errorf("unrecognized time of day (expected 14:30, 2:30pm, 1430, noon or midnight): %s", s)
+}
+
+// cutMeridiem removes an am or pm suffix from s.
+func cutMeridiem(s string) (clock string, pm bool, ok bool) {
+	for _, suffix := range []string{"am", "a.m.", "pm", "p.m."} {
+		if strings.HasSuffix(s, suffix) {
+			return strings.TrimSpace(strings.TrimSuffix(s, suffix)), suffix[0] == 'p', true
+		}
+	}
+	return s, false, false
+}
+
+// parse12HourTOD parses a time of day such as "2" or "2:30" on a 12-hour
+// clock.
+func parse12HourTOD(clock string, pm bool) (TOD, error) {
+	if !strings.Contains(clock, ":") {
+		clock += ":00"
+	}
+
+	tod, err := ParseTOD(clock)
+	if err != nil {
+		return TOD{}, err
+	}
+	if tod.Hour < 1 || tod.Hour > 12 {
+		return TOD{}, fmt.Errorf("invalid hour: %v (expected 1 to 12)", tod.Hour)
+	}
+
+	tod.Hour %= 12
+	if pm {
+		tod.Hour += 12
+	}
+	return tod, nil
+}
+
+// parseFraction parses the digits after the decimal point of a second into
+// nanoseconds.
+func parseFraction(s string) (int, error) {
+	if len(s) == 0 || len(s) > 9 {
+		return 0, fmt.Errorf("expected 1 to 9 digits: %q", s)
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("expected 1 to 9 digits: %q", s)
+		}
+	}
+	return strconv.Atoi(s + strings.Repeat("0", 9-len(s)))
+}
+
+// TODOf returns the time of day shown by t.
+func TODOf(t time.Time) TOD {
+	return TOD{Hour: t.Hour(), Minute: t.Minute(), Second: t.Second(), Nanosecond: t.Nanosecond()}
+}
+
+// TODIn returns the time of day shown by t in loc.
+func TODIn(t time.Time, loc *time.Location) TOD {
+	return TODOf(t.In(loc))
+}
+
+// SinceMidnight returns the time of day as the time elapsed since midnight on
+// a day without DST transitions.
+func (t TOD) SinceMidnight() time.Duration {
+	return time.Duration(t.Hour)*time.Hour +
+		time.Duration(t.Minute)*time.Minute +
+		time.Duration(t.Second)*time.Second +
+		time.Duration(t.Nanosecond)
+}
+
+// Compare returns -1 if t is before u, +1 if t is after u and 0 if they are
+// equal.
+func (t TOD) Compare(u TOD) int {
+	switch d := t.SinceMidnight() - u.SinceMidnight(); {
+	case d < 0:
+		return -1
+	case d > 0:
+		return 1
+	}
+	return 0
+}
+
+// Before reports whether t is before u.
+func (t TOD) Before(u TOD) bool {
+	return t.Compare(u) < 0
+}
+
+// After reports whether t is after u.
+func (t TOD) After(u TOD) bool {
+	return t.Compare(u) > 0
+}
+
+// Equal reports whether t and u are the same time of day.
+func (t TOD) Equal(u TOD) bool {
+	return t.Compare(u) == 0
+}
+
+// Add returns the time of day d after t, wrapping around midnight. The result
+// is always before EndOfDay.
+func (t TOD) Add(d time.Duration) TOD {
+	d = (t.SinceMidnight() + d) % (24 * time.Hour)
+	if d < 0 {
+		d += 24 * time.Hour
+	}
+	return todSinceMidnight(d)
+}
+
+// Sub returns the duration t-u on a day without DST transitions.
+func (t TOD) Sub(u TOD) time.Duration {
+	return t.SinceMidnight() - u.SinceMidnight()
+}
+
+// On returns the instant at which clocks in loc show t on the calendar date of
+// d. Times that are skipped by a DST transition are moved forward by the length
+// of the transition and times that are repeated resolve to their first
+// occurrence.
+func (t TOD) On(d time.Time, loc *time.Location) time.Time {
+	return wallClock(date(d), t, loc)
+}
+
+// todSinceMidnight returns the time of day that is d after midnight. d must
+// be at most 24 hours.
+func todSinceMidnight(d time.Duration) TOD {
+	return TOD{
+		Hour:       int(d / time.Hour),
+		Minute:     int(d % time.Hour / time.Minute),
+		Second:     int(d % time.Minute / time.Second),
+		Nanosecond: int(d % time.Second),
+	}
 }
@@ -0,0 +1,165 @@
+package timewindow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+func ParseTODMonthWindow(start, end string, daysOfMonth []string) (*TODMonthWindow, error) {
+	s, err := parseStartTOD(start)
+	if err != nil {
+		return nil, fmt.Errorf("start: %w", err)
+	}
+
+	e, err := ParseTOD(end)
+	if err != nil {
+		return nil, fmt.Errorf("end: %w", err)
+	}
+
+	m, err := ParseMonthDays(daysOfMonth)
+	if err != nil {
+		return nil, fmt.Errorf("days of month: %w", err)
+	}
+
+	return &TODMonthWindow{Start: s, End: e, MonthDays: m}, nil
+}
+
+// todMonthWindowJSON is the JSON and YAML representation of a TODMonthWindow.
+type todMonthWindowJSON struct {
+	Start    string `json:"start" yaml:"start"`
+	End      string `json:"end" yaml:"end"`
+	Days     *[]int `json:"days" yaml:"days"`
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
+
+func newTODMonthWindowJSON(w TODMonthWindow) (todMonthWindowJSON, error) {
+	tz, err := timezoneName(w.Location)
+	if err != nil {
+		return todMonthWindowJSON{}, err
+	}
+	days := w.MonthDays.days()
+	return todMonthWindowJSON{Start: w.Start.String(), End: w.End.String(), Days: &days, Timezone: tz}, nil
+}
+
+// window parses v with the same validation as ParseTODMonthWindow. The days
+// are required so that a missing field does not silently result in a window
+// that is never open.
+func (v todMonthWindowJSON) window() (*TODMonthWindow, error) {
+	if v.Days == nil {
+		return nil, errors.New("missing days")
+	}
+
+	days := make([]string, len(*v.Days))
+	for i, d := range *v.Days {
+		days[i] = strconv.Itoa(d)
+	}
+	w, err := ParseTODMonthWindow(v.Start, v.End, days)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Location, err = parseTimezone(v.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// MarshalJSON implements json.Marshaler. The window is written as an object
+// such as {"start":"22:00","end":"02:00","days":[1,15,-1],"timezone":"UTC"}.
+func (w TODMonthWindow) MarshalJSON() ([]byte, error) {
+	v, err := newTODMonthWindowJSON(w)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The fields are validated like
+// the arguments of ParseTODMonthWindow.
+func (w *TODMonthWindow) UnmarshalJSON(b []byte) error {
+	var v todMonthWindowJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	return w.set(v)
+}
+
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2 and
+// gopkg.in/yaml.v3.
+func (w TODMonthWindow) MarshalYAML() (interface{}, error) {
+	return newTODMonthWindowJSON(w)
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2,
+// which gopkg.in/yaml.v3 also supports.
+func (w *TODMonthWindow) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v todMonthWindowJSON
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	return w.set(v)
+}
+
+// set sets w to the window parsed from v.
+func (w *TODMonthWindow) set(v todMonthWindowJSON) error {
+	parsed, err := v.window()
+	if err != nil {
+		return err
+	}
+	*w = *parsed
+	return nil
+}
+
+// TODMonthWindow is a window from Start to End on the matching days of every
+// month. Windows that cross midnight end on the following day.
+type TODMonthWindow struct {
+	MonthDays
+	Start TOD
+	End   TOD
+
+	// Location is the time zone that Start, End and MonthDays are expressed
+	// in. If nil, the window is resolved in the location of the time that is
+	// passed in.
+	Location *time.Location
+}
+
+var _ Window = &TODMonthWindow{}
+
+// WithinWindow returns true if within the window. It also returns the time until
+// the next window.
+func (w *TODMonthWindow) WithinWindow(now time.Time) WindowResult {
+	return withinOccurrences(w, now)
+}
+
+// NextOccurrence returns the first window that starts after t on a matching
+// day.
+func (w *TODMonthWindow) NextOccurrence(t time.Time) (Interval, bool) {
+	return w.dayWindow().nextOccurrence(t)
+}
+
+// PreviousOccurrence returns the last window that starts at or before t on a
+// matching day.
+func (w *TODMonthWindow) PreviousOccurrence(t time.Time) (Interval, bool) {
+	return w.dayWindow().previousOccurrence(t)
+}
+
+// Occurrences returns the windows that overlap the range from from (inclusive)
+// to to (exclusive).
+func (w *TODMonthWindow) Occurrences(from, to time.Time) []Interval {
+	return occurrences(w, from, to)
+}
+
+// Iterate returns an iterator over the windows, beginning with the first one
+// that ends after from.
+func (w *TODMonthWindow) Iterate(from time.Time) *OccurrenceIterator {
+	return newOccurrenceIterator(w, from)
+}
+
+// dayWindow returns the window with the days that it matches.
+func (w *TODMonthWindow) dayWindow() dayWindow {
+	return dayWindow{start: w.Start, end: w.End, location: w.Location, matches: w.MonthDays.Matches}
+}
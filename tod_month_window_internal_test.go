@@ -0,0 +1,210 @@
+package timewindow
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTODMonthWindow(t *testing.T) {
+	cases := []struct {
+		name string
+
+		window TODMonthWindow
+		now    time.Time
+
+		result   WindowResult
+		previous Interval
+		next     Interval
+	}{
+		{
+			name: "first-and-fifteenth-between",
+			window: TODMonthWindow{
+				Start:     TOD{Hour: 22},
+				End:       TOD{Hour: 2},
+				MonthDays: MonthDays{1: true, 15: true},
+			},
+			now: time.Date(2021, time.February, 10, 0, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 5*24*time.Hour + 22*time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.February, 1, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.February, 2, 2, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.February, 15, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.February, 16, 2, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "last-day-overnight-into-next-month",
+			window: TODMonthWindow{
+				Start:     TOD{Hour: 22},
+				End:       TOD{Hour: 2},
+				MonthDays: MonthDays{-1: true},
+			},
+			now: time.Date(2021, time.March, 1, 1, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 30*24*time.Hour + 21*time.Hour,
+				TTEnd:   time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.February, 28, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.March, 1, 2, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.March, 31, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.April, 1, 2, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "last-day-of-leap-february",
+			window: TODMonthWindow{
+				Start:     TOD{Hour: 10},
+				End:       TOD{Hour: 12},
+				MonthDays: MonthDays{-1: true},
+			},
+			now: time.Date(2020, time.February, 28, 10, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 24 * time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2020, time.January, 31, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2020, time.January, 31, 12, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2020, time.February, 29, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2020, time.February, 29, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "thirty-first-skips-short-months",
+			window: TODMonthWindow{
+				Start:     TOD{Hour: 10},
+				End:       TOD{Hour: 12},
+				MonthDays: MonthDays{31: true},
+			},
+			now: time.Date(2021, time.January, 31, 11, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 59*24*time.Hour - time.Hour,
+				TTEnd:   time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.January, 31, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.January, 31, 12, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.March, 31, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.March, 31, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "location",
+			window: TODMonthWindow{
+				Start:     TOD{Hour: 1},
+				End:       TOD{Hour: 3},
+				MonthDays: MonthDays{1: true},
+				Location:  berlin,
+			},
+			now: time.Date(2021, time.May, 31, 23, 30, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 30*24*time.Hour - 30*time.Minute,
+				TTEnd:   time.Hour + 30*time.Minute,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.June, 1, 1, 0, 0, 0, berlin),
+				End:   time.Date(2021, time.June, 1, 3, 0, 0, 0, berlin),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.July, 1, 1, 0, 0, 0, berlin),
+				End:   time.Date(2021, time.July, 1, 3, 0, 0, 0, berlin),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result := c.window.WithinWindow(c.now)
+			require.Equal(t, c.result.Within, result.Within)
+			require.Equal(t, c.result.TTStart.String(), result.TTStart.String())
+			require.Equal(t, c.result.TTEnd.String(), result.TTEnd.String())
+
+			previous, ok := c.window.PreviousOccurrence(c.now)
+			require.True(t, ok)
+			require.Equal(t, c.previous.Start.String(), previous.Start.String())
+			require.Equal(t, c.previous.End.String(), previous.End.String())
+
+			next, ok := c.window.NextOccurrence(c.now)
+			require.True(t, ok)
+			require.Equal(t, c.next.Start.String(), next.Start.String())
+			require.Equal(t, c.next.End.String(), next.End.String())
+		})
+	}
+}
+
+func TestTODMonthWindowNever(t *testing.T) {
+	window := TODMonthWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 12}, MonthDays: MonthDays{}}
+	now := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	require.Equal(t, Never, window.WithinWindow(now).TTStart)
+}
+
+func TestTODMonthWindowJSON(t *testing.T) {
+	w := TODMonthWindow{
+		Start:     TOD{Hour: 22},
+		End:       TOD{Hour: 2},
+		MonthDays: MonthDays{-1: true, 15: true, 1: true, -2: true},
+		Location:  berlin,
+	}
+
+	b, err := json.Marshal(w)
+	require.NoError(t, err)
+	require.Equal(t, `{"start":"22:00","end":"02:00","days":[1,15,-1,-2],"timezone":"Europe/Berlin"}`, string(b))
+
+	var parsed TODMonthWindow
+	require.NoError(t, json.Unmarshal(b, &parsed))
+	require.Equal(t, w, parsed)
+}
+
+func TestTODMonthWindowJSONSadPath(t *testing.T) {
+	cases := []struct {
+		json string
+		err  string
+	}{
+		{
+			json: `{"start":"22:00","end":"02:00"}`,
+			err:  "missing days",
+		},
+		{
+			json: `{"start":"22:00","end":"02:00","days":[32]}`,
+			err:  "days of month: unrecognized day of month: 32",
+		},
+		{
+			json: `{"start":"24:00","end":"02:00","days":[1]}`,
+			err:  "start: 24:00 is only valid as the end of a window",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.json, func(t *testing.T) {
+			var w TODMonthWindow
+			require.EqualError(t, json.Unmarshal([]byte(c.json), &w), c.err)
+		})
+	}
+
+	_, err := json.Marshal(TODMonthWindow{MonthDays: MonthDays{1: true}, Location: time.FixedZone("X", 3600)})
+	require.Contains(t, err.Error(), "is not an IANA time zone name")
+}
@@ -0,0 +1,26 @@
+package timewindow_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nstogner/timewindow"
+)
+
+func ExampleTODMonthWindow() {
+	window, err := timewindow.ParseTODMonthWindow("22:00", "02:00", []string{"1", "15", "last"})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	from := time.Date(2021, time.February, 1, 0, 0, 0, 0, time.UTC)
+	for _, o := range window.Occurrences(from, from.AddDate(0, 1, 0)) {
+		fmt.Println(o.Start.Format(time.RFC1123), "-", o.End.Format(time.RFC1123))
+	}
+	// Output:
+	// Sun, 31 Jan 2021 22:00:00 UTC - Mon, 01 Feb 2021 02:00:00 UTC
+	// Mon, 01 Feb 2021 22:00:00 UTC - Tue, 02 Feb 2021 02:00:00 UTC
+	// Mon, 15 Feb 2021 22:00:00 UTC - Tue, 16 Feb 2021 02:00:00 UTC
+	// Sun, 28 Feb 2021 22:00:00 UTC - Mon, 01 Mar 2021 02:00:00 UTC
+}
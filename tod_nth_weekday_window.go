@@ -0,0 +1,162 @@
+package timewindow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+func ParseTODNthWeekdayWindow(start, end string, nthWeekdays []string) (*TODNthWeekdayWindow, error) {
+	s, err := parseStartTOD(start)
+	if err != nil {
+		return nil, fmt.Errorf("start: %w", err)
+	}
+
+	e, err := ParseTOD(end)
+	if err != nil {
+		return nil, fmt.Errorf("end: %w", err)
+	}
+
+	n, err := ParseNthWeekdays(nthWeekdays)
+	if err != nil {
+		return nil, fmt.Errorf("weekdays of month: %w", err)
+	}
+
+	return &TODNthWeekdayWindow{Start: s, End: e, NthWeekdays: n}, nil
+}
+
+// todNthWeekdayWindowJSON is the JSON and YAML representation of a
+// TODNthWeekdayWindow.
+type todNthWeekdayWindowJSON struct {
+	Start    string    `json:"start" yaml:"start"`
+	End      string    `json:"end" yaml:"end"`
+	Days     *[]string `json:"days" yaml:"days"`
+	Timezone string    `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
+
+func newTODNthWeekdayWindowJSON(w TODNthWeekdayWindow) (todNthWeekdayWindowJSON, error) {
+	tz, err := timezoneName(w.Location)
+	if err != nil {
+		return todNthWeekdayWindowJSON{}, err
+	}
+	days := w.NthWeekdays.names()
+	return todNthWeekdayWindowJSON{Start: w.Start.String(), End: w.End.String(), Days: &days, Timezone: tz}, nil
+}
+
+// window parses v with the same validation as ParseTODNthWeekdayWindow. The
+// days are required so that a missing field does not silently result in a
+// window that is never open.
+func (v todNthWeekdayWindowJSON) window() (*TODNthWeekdayWindow, error) {
+	if v.Days == nil {
+		return nil, errors.New("missing days")
+	}
+
+	w, err := ParseTODNthWeekdayWindow(v.Start, v.End, *v.Days)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Location, err = parseTimezone(v.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// MarshalJSON implements json.Marshaler. The window is written as an object
+// such as {"start":"22:00","end":"02:00","days":["2nd tue","last fri"],"timezone":"UTC"}.
+func (w TODNthWeekdayWindow) MarshalJSON() ([]byte, error) {
+	v, err := newTODNthWeekdayWindowJSON(w)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The fields are validated like
+// the arguments of ParseTODNthWeekdayWindow.
+func (w *TODNthWeekdayWindow) UnmarshalJSON(b []byte) error {
+	var v todNthWeekdayWindowJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	return w.set(v)
+}
+
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2 and
+// gopkg.in/yaml.v3.
+func (w TODNthWeekdayWindow) MarshalYAML() (interface{}, error) {
+	return newTODNthWeekdayWindowJSON(w)
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2,
+// which gopkg.in/yaml.v3 also supports.
+func (w *TODNthWeekdayWindow) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v todNthWeekdayWindowJSON
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	return w.set(v)
+}
+
+// set sets w to the window parsed from v.
+func (w *TODNthWeekdayWindow) set(v todNthWeekdayWindowJSON) error {
+	parsed, err := v.window()
+	if err != nil {
+		return err
+	}
+	*w = *parsed
+	return nil
+}
+
+// TODNthWeekdayWindow is a window from Start to End on the matching weekdays of
+// every month, such as the second Tuesday. Windows that cross midnight end on
+// the following day.
+type TODNthWeekdayWindow struct {
+	NthWeekdays
+	Start TOD
+	End   TOD
+
+	// Location is the time zone that Start, End and NthWeekdays are
+	// expressed in. If nil, the window is resolved in the location of the
+	// time that is passed in.
+	Location *time.Location
+}
+
+var _ Window = &TODNthWeekdayWindow{}
+
+// WithinWindow returns true if within the window. It also returns the time until
+// the next window.
+func (w *TODNthWeekdayWindow) WithinWindow(now time.Time) WindowResult {
+	return withinOccurrences(w, now)
+}
+
+// NextOccurrence returns the first window that starts after t on a matching
+// day.
+func (w *TODNthWeekdayWindow) NextOccurrence(t time.Time) (Interval, bool) {
+	return w.dayWindow().nextOccurrence(t)
+}
+
+// PreviousOccurrence returns the last window that starts at or before t on a
+// matching day.
+func (w *TODNthWeekdayWindow) PreviousOccurrence(t time.Time) (Interval, bool) {
+	return w.dayWindow().previousOccurrence(t)
+}
+
+// Occurrences returns the windows that overlap the range from from (inclusive)
+// to to (exclusive).
+func (w *TODNthWeekdayWindow) Occurrences(from, to time.Time) []Interval {
+	return occurrences(w, from, to)
+}
+
+// Iterate returns an iterator over the windows, beginning with the first one
+// that ends after from.
+func (w *TODNthWeekdayWindow) Iterate(from time.Time) *OccurrenceIterator {
+	return newOccurrenceIterator(w, from)
+}
+
+// dayWindow returns the window with the days that it matches.
+func (w *TODNthWeekdayWindow) dayWindow() dayWindow {
+	return dayWindow{start: w.Start, end: w.End, location: w.Location, matches: w.NthWeekdays.Matches}
+}
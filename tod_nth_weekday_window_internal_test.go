@@ -0,0 +1,175 @@
+package timewindow
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTODNthWeekdayWindow(t *testing.T) {
+	patchTuesday := TODNthWeekdayWindow{
+		Start:       TOD{Hour: 22},
+		End:         TOD{Hour: 2},
+		NthWeekdays: NthWeekdays{{N: 2, Weekday: time.Tuesday}: true},
+	}
+
+	cases := []struct {
+		name string
+
+		window TODNthWeekdayWindow
+		now    time.Time
+
+		result   WindowResult
+		previous Interval
+		next     Interval
+	}{
+		{
+			name:   "before",
+			window: patchTuesday,
+			now:    time.Date(2021, time.June, 8, 12, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 10 * time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.May, 11, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.May, 12, 2, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.June, 8, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 9, 2, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "within-after-midnight",
+			window: patchTuesday,
+			now:    time.Date(2021, time.June, 9, 1, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 34*24*time.Hour + 21*time.Hour,
+				TTEnd:   time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.June, 8, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 9, 2, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.July, 13, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.July, 14, 2, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "last-friday",
+			window: TODNthWeekdayWindow{
+				Start:       TOD{Hour: 18},
+				End:         TOD{Hour: 20},
+				NthWeekdays: NthWeekdays{{N: -1, Weekday: time.Friday}: true},
+			},
+			now: time.Date(2021, time.April, 23, 19, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 7*24*time.Hour - time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.March, 26, 18, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.March, 26, 20, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.April, 30, 18, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.April, 30, 20, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "fifth-monday-skips-months",
+			window: TODNthWeekdayWindow{
+				Start:       TOD{Hour: 10},
+				End:         TOD{Hour: 12},
+				NthWeekdays: NthWeekdays{{N: 5, Weekday: time.Monday}: true},
+			},
+			now: time.Date(2021, time.April, 1, 0, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 60*24*time.Hour + 10*time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.March, 29, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.March, 29, 12, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.May, 31, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.May, 31, 12, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result := c.window.WithinWindow(c.now)
+			require.Equal(t, c.result.Within, result.Within)
+			require.Equal(t, c.result.TTStart.String(), result.TTStart.String())
+			require.Equal(t, c.result.TTEnd.String(), result.TTEnd.String())
+
+			previous, ok := c.window.PreviousOccurrence(c.now)
+			require.True(t, ok)
+			require.Equal(t, c.previous.Start.String(), previous.Start.String())
+			require.Equal(t, c.previous.End.String(), previous.End.String())
+
+			next, ok := c.window.NextOccurrence(c.now)
+			require.True(t, ok)
+			require.Equal(t, c.next.Start.String(), next.Start.String())
+			require.Equal(t, c.next.End.String(), next.End.String())
+		})
+	}
+}
+
+func TestTODNthWeekdayWindowJSON(t *testing.T) {
+	w := TODNthWeekdayWindow{
+		Start: TOD{Hour: 22},
+		End:   TOD{Hour: 2},
+		NthWeekdays: NthWeekdays{
+			{N: -1, Weekday: time.Friday}:  true,
+			{N: -2, Weekday: time.Friday}:  true,
+			{N: 2, Weekday: time.Tuesday}:  true,
+			{N: 1, Weekday: time.Friday}:   true,
+			{N: 4, Weekday: time.Thursday}: true,
+		},
+		Location: berlin,
+	}
+
+	b, err := json.Marshal(w)
+	require.NoError(t, err)
+	require.Equal(t, `{"start":"22:00","end":"02:00","days":["2nd Tue","4th Thu","1st Fri","last Fri","2nd-last Fri"],"timezone":"Europe/Berlin"}`, string(b))
+
+	var parsed TODNthWeekdayWindow
+	require.NoError(t, json.Unmarshal(b, &parsed))
+	require.Equal(t, w, parsed)
+}
+
+func TestTODNthWeekdayWindowJSONSadPath(t *testing.T) {
+	cases := []struct {
+		json string
+		err  string
+	}{
+		{
+			json: `{"start":"22:00","end":"02:00"}`,
+			err:  "missing days",
+		},
+		{
+			json: `{"start":"22:00","end":"02:00","days":["6th tue"]}`,
+			err:  "weekdays of month: unrecognized ordinal: 6th",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.json, func(t *testing.T) {
+			var w TODNthWeekdayWindow
+			require.EqualError(t, json.Unmarshal([]byte(c.json), &w), c.err)
+		})
+	}
+}
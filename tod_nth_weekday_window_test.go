@@ -0,0 +1,25 @@
+package timewindow_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nstogner/timewindow"
+)
+
+func ExampleTODNthWeekdayWindow() {
+	window, err := timewindow.ParseTODNthWeekdayWindow("22:00", "02:00", []string{"2nd tue"})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	from := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	for _, o := range window.Occurrences(from, from.AddDate(0, 3, 0)) {
+		fmt.Println(o.Start.Format(time.RFC1123), "-", o.End.Format(time.RFC1123))
+	}
+	// Output:
+	// Tue, 08 Jun 2021 22:00:00 UTC - Wed, 09 Jun 2021 02:00:00 UTC
+	// Tue, 13 Jul 2021 22:00:00 UTC - Wed, 14 Jul 2021 02:00:00 UTC
+	// Tue, 10 Aug 2021 22:00:00 UTC - Wed, 11 Aug 2021 02:00:00 UTC
+}
@@ -2,6 +2,7 @@ package timewindow
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -23,6 +24,26 @@ func TestParseTODHappyPath(t *testing.T) {
 			s:   "11:22",
 			tod: TOD{Hour: 11, Minute: 22},
 		},
+		{
+			s:   "12:34:56",
+			tod: TOD{Hour: 12, Minute: 34, Second: 56},
+		},
+		{
+			s:   "23:59:59.999999999",
+			tod: TOD{Hour: 23, Minute: 59, Second: 59, Nanosecond: 999999999},
+		},
+		{
+			s:   "00:00:01.5",
+			tod: TOD{Second: 1, Nanosecond: 500000000},
+		},
+		{
+			s:   "24:00",
+			tod: EndOfDay,
+		},
+		{
+			s:   "24:00:00",
+			tod: TOD{Hour: 24},
+		},
 	}
 
 	for _, c := range cases {
@@ -65,6 +86,41 @@ func TestParseTODSadPath(t *testing.T) {
 			s:       "00:61",
 			errText: "invalid minute",
 		},
+		{
+			name:    "after-end-of-day",
+			s:       "24:00:01",
+			errText: "invalid time after 24:00",
+		},
+		{
+			name:    "too-many-seconds",
+			s:       "00:00:60",
+			errText: "invalid second",
+		},
+		{
+			name:    "too-many-parts",
+			s:       "00:00:00:00",
+			errText: "invalid format",
+		},
+		{
+			name:    "bad-second",
+			s:       "00:00:xx",
+			errText: "parsing second",
+		},
+		{
+			name:    "empty-fraction",
+			s:       "00:00:00.",
+			errText: "parsing fraction of second",
+		},
+		{
+			name:    "too-long-fraction",
+			s:       "00:00:00.1234567890",
+			errText: "parsing fraction of second",
+		},
+		{
+			name:    "signed-fraction",
+			s:       "00:00:00.-5",
+			errText: "parsing fraction of second",
+		},
 	}
 
 	for _, c := range cases {
@@ -74,3 +130,180 @@ func TestParseTODSadPath(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTODLenient(t *testing.T) {
+	cases := []struct {
+		s   string
+		tod TOD
+		err string
+	}{
+		{s: "14:30", tod: TOD{Hour: 14, Minute: 30}},
+		{s: "14:30:15", tod: TOD{Hour: 14, Minute: 30, Second: 15}},
+		{s: "2pm", tod: TOD{Hour: 14}},
+		{s: "2:30 PM", tod: TOD{Hour: 14, Minute: 30}},
+		{s: "12am", tod: TOD{Hour: 0}},
+		{s: "12:15 p.m.", tod: TOD{Hour: 12, Minute: 15}},
+		{s: "9:05:30 a.m.", tod: TOD{Hour: 9, Minute: 5, Second: 30}},
+		{s: "1430", tod: TOD{Hour: 14, Minute: 30}},
+		{s: "0930", tod: TOD{Hour: 9, Minute: 30}},
+		{s: "930", tod: TOD{Hour: 9, Minute: 30}},
+		{s: " Noon ", tod: TOD{Hour: 12}},
+		{s: "midnight", tod: TOD{}},
+
+		{s: "13pm", err: "12-hour clock: invalid hour: 13 (expected 1 to 12)"},
+		{s: "0am", err: "12-hour clock: invalid hour: 0 (expected 1 to 12)"},
+		{s: "2:75pm", err: "12-hour clock: invalid minute: 75"},
+		{s: "2400", tod: EndOfDay},
+		{s: "24:30", err: "24-hour clock: invalid time after 24:00: 24:30"},
+		{s: "25:00", err: "24-hour clock: invalid hour: 25"},
+		{s: "2500", err: "military time: invalid hour: 25"},
+		{s: "1275", err: "military time: invalid minute: 75"},
+		{s: "14", err: "unrecognized time of day (expected 14:30, 2:30pm, 1430, noon or midnight): 14"},
+		{s: "teatime", err: "unrecognized time of day (expected 14:30, 2:30pm, 1430, noon or midnight): teatime"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			tod, err := ParseTODLenient(c.s)
+			if c.err != "" {
+				require.EqualError(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.tod, tod)
+		})
+	}
+}
+
+func TestTODCompare(t *testing.T) {
+	a := TOD{Hour: 9, Minute: 30}
+	b := TOD{Hour: 9, Minute: 30, Nanosecond: 1}
+
+	require.Equal(t, -1, a.Compare(b))
+	require.Equal(t, 1, b.Compare(a))
+	require.Equal(t, 0, a.Compare(a))
+	require.True(t, a.Before(b))
+	require.False(t, a.After(b))
+	require.True(t, b.After(a))
+	require.True(t, a.Equal(TOD{Hour: 9, Minute: 30}))
+	require.True(t, EndOfDay.After(TOD{Hour: 23, Minute: 59, Second: 59}))
+}
+
+func TestTODArithmetic(t *testing.T) {
+	cases := []struct {
+		name string
+		tod  TOD
+		d    time.Duration
+		want TOD
+	}{
+		{
+			name: "forward",
+			tod:  TOD{Hour: 9, Minute: 30},
+			d:    90 * time.Minute,
+			want: TOD{Hour: 11},
+		},
+		{
+			name: "wrap-forward",
+			tod:  TOD{Hour: 22},
+			d:    4 * time.Hour,
+			want: TOD{Hour: 2},
+		},
+		{
+			name: "wrap-backward",
+			tod:  TOD{Hour: 1},
+			d:    -2 * time.Hour,
+			want: TOD{Hour: 23},
+		},
+		{
+			name: "multiple-days",
+			tod:  TOD{Hour: 12},
+			d:    50*time.Hour + time.Second,
+			want: TOD{Hour: 14, Second: 1},
+		},
+		{
+			name: "end-of-day",
+			tod:  EndOfDay,
+			d:    time.Nanosecond,
+			want: TOD{Nanosecond: 1},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.want, c.tod.Add(c.d))
+		})
+	}
+
+	require.Equal(t, 90*time.Minute, TOD{Hour: 11}.Sub(TOD{Hour: 9, Minute: 30}))
+	require.Equal(t, -20*time.Hour, TOD{Hour: 2}.Sub(TOD{Hour: 22}))
+	require.Equal(t, 24*time.Hour, EndOfDay.SinceMidnight())
+}
+
+func TestTODOf(t *testing.T) {
+	ts := time.Date(2021, time.June, 7, 14, 30, 15, 500, time.UTC)
+	require.Equal(t, TOD{Hour: 14, Minute: 30, Second: 15, Nanosecond: 500}, TODOf(ts))
+	require.Equal(t, TOD{Hour: 16, Minute: 30, Second: 15, Nanosecond: 500}, TODIn(ts, berlin))
+}
+
+func TestTODOn(t *testing.T) {
+	day := time.Date(2021, time.March, 14, 18, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name string
+		tod  TOD
+		loc  *time.Location
+		want time.Time
+	}{
+		{
+			name: "utc",
+			tod:  TOD{Hour: 9, Minute: 30},
+			loc:  time.UTC,
+			want: time.Date(2021, time.March, 14, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "dst-skipped",
+			tod:  TOD{Hour: 2, Minute: 30},
+			loc:  newYork,
+			want: time.Date(2021, time.March, 14, 7, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "end-of-day",
+			tod:  EndOfDay,
+			loc:  newYork,
+			want: time.Date(2021, time.March, 15, 4, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.want.String(), c.tod.On(day, c.loc).UTC().String())
+		})
+	}
+}
+
+func TestTODText(t *testing.T) {
+	cases := []struct {
+		tod TOD
+		s   string
+	}{
+		{tod: TOD{Hour: 9, Minute: 5}, s: "09:05"},
+		{tod: TOD{Hour: 23, Minute: 59, Second: 59}, s: "23:59:59"},
+		{tod: TOD{Hour: 12, Nanosecond: 500000000}, s: "12:00:00.5"},
+		{tod: EndOfDay, s: "24:00"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			text, err := c.tod.MarshalText()
+			require.NoError(t, err)
+			require.Equal(t, c.s, string(text))
+
+			var tod TOD
+			require.NoError(t, tod.UnmarshalText(text))
+			require.Equal(t, c.tod, tod)
+		})
+	}
+
+	var tod TOD
+	require.EqualError(t, tod.UnmarshalText([]byte("25:00")), "invalid hour: 25")
+}
@@ -1,12 +1,14 @@
 package timewindow
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 )
 
 func ParseTODWeekWindow(start, end string, weekdays []string) (*TODWeekWindow, error) {
-	s, err := ParseTOD(start)
+	s, err := parseStartTOD(start)
 	if err != nil {
 		return nil, fmt.Errorf("start: %w", err)
 	}
@@ -16,52 +18,417 @@ func ParseTODWeekWindow(start, end string, weekdays []string) (*TODWeekWindow, e
 		return nil, fmt.Errorf("end: %w", err)
 	}
 
-	w, err := ParseWeekdays(weekdays)
+	w, err := ParseWeekdaySet(weekdays)
 	if err != nil {
 		return nil, fmt.Errorf("weekdays: %w", err)
 	}
 
-	return &TODWeekWindow{Start: s, End: e, Weekdays: w}, nil
+	return &TODWeekWindow{Start: s, End: e, Days: w}, nil
 }
 
+// ParseTODWeekWindowInLocation is like ParseTODWeekWindow but resolves the window
+// in the location with the given IANA name (for example "Europe/Berlin").
+func ParseTODWeekWindowInLocation(start, end string, weekdays []string, location string) (*TODWeekWindow, error) {
+	w, err := ParseTODWeekWindow(start, end, weekdays)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(location)
+	if err != nil {
+		return nil, fmt.Errorf("location: %w", err)
+	}
+	w.Location = loc
+
+	return w, nil
+}
+
+// NewTODWeekWindowFor returns a window that starts at start on the given
+// weekdays and lasts for d, which may be longer than a day.
+func NewTODWeekWindowFor(start TOD, d time.Duration, weekdays WeekdaySet) *TODWeekWindow {
+	return &TODWeekWindow{Start: start, End: start, Duration: d, Days: weekdays}
+}
+
+// ParseTODWeekWindowFor is like ParseTODWeekWindow but takes the length of the
+// window as a duration (for example "56h") instead of an end time.
+func ParseTODWeekWindowFor(start, duration string, weekdays []string) (*TODWeekWindow, error) {
+	s, err := parseStartTOD(start)
+	if err != nil {
+		return nil, fmt.Errorf("start: %w", err)
+	}
+
+	d, err := parseWindowDuration(duration)
+	if err != nil {
+		return nil, fmt.Errorf("duration: %w", err)
+	}
+
+	w, err := ParseWeekdaySet(weekdays)
+	if err != nil {
+		return nil, fmt.Errorf("weekdays: %w", err)
+	}
+
+	return NewTODWeekWindowFor(s, d, w), nil
+}
+
+// NewAllDayWindow returns a window that is open for the whole of each of the
+// given weekdays, from 00:00 to 24:00.
+func NewAllDayWindow(weekdays WeekdaySet) *TODWeekWindow {
+	return &TODWeekWindow{Start: TOD{}, End: EndOfDay, Days: weekdays}
+}
+
+// String returns the window in the form accepted by ParseWindow, such as
+// "Mon-Fri 22:00-02:00 America/Chicago". A window without weekdays is written
+// with the weekday group "none". As with TODWindow.String, a Location that is
+// not an IANA time zone cannot be parsed back.
+func (w TODWeekWindow) String() string {
+	days := w.days()
+	if days.Len() == 0 {
+		return "none " + formatWindowSpec(w.Start, w.End, w.Duration, w.Location)
+	}
+	return days.String() + " " + formatWindowSpec(w.Start, w.End, w.Duration, w.Location)
+}
+
+// MarshalText implements encoding.TextMarshaler using String. It returns an
+// error if Location is not an IANA time zone.
+func (w TODWeekWindow) MarshalText() ([]byte, error) {
+	if _, err := timezoneName(w.Location); err != nil {
+		return nil, err
+	}
+	return []byte(w.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It parses a single window
+// with weekdays in the form accepted by ParseWindow.
+func (w *TODWeekWindow) UnmarshalText(text []byte) error {
+	parsed, hasWeekdays, err := parseWeekWindowSpec(string(text))
+	if err != nil {
+		return err
+	}
+	if !hasWeekdays {
+		return errors.New("missing weekdays: " + string(text))
+	}
+	*w = *parsed
+	return nil
+}
+
+// todWeekWindowJSON is the JSON and YAML representation of a TODWeekWindow.
+type todWeekWindowJSON struct {
+	Start    string    `json:"start" yaml:"start"`
+	End      string    `json:"end,omitempty" yaml:"end,omitempty"`
+	Duration string    `json:"duration,omitempty" yaml:"duration,omitempty"`
+	Weekdays *[]string `json:"weekdays" yaml:"weekdays"`
+	Timezone string    `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
+
+func newTODWeekWindowJSON(w TODWeekWindow) (todWeekWindowJSON, error) {
+	d, err := newTODWindowJSON(TODWindow{Start: w.Start, End: w.End, Duration: w.Duration, Location: w.Location})
+	if err != nil {
+		return todWeekWindowJSON{}, err
+	}
+	names := w.days().names()
+	return todWeekWindowJSON{
+		Start:    d.Start,
+		End:      d.End,
+		Duration: d.Duration,
+		Weekdays: &names,
+		Timezone: d.Timezone,
+	}, nil
+}
+
+// window parses v with the same validation as ParseTODWeekWindow and
+// ParseTODWeekWindowFor. The weekdays are required so that a missing field
+// does not silently result in a window that is never open; an empty list or
+// ["none"] must be given for that.
+func (v todWeekWindowJSON) window() (*TODWeekWindow, error) {
+	if v.Weekdays == nil {
+		return nil, errors.New("missing weekdays")
+	}
+
+	d, err := todWindowJSON{Start: v.Start, End: v.End, Duration: v.Duration, Timezone: v.Timezone}.window()
+	if err != nil {
+		return nil, err
+	}
+
+	wds, err := ParseWeekdaySet(*v.Weekdays)
+	if err != nil {
+		return nil, fmt.Errorf("weekdays: %w", err)
+	}
+
+	return &TODWeekWindow{
+		Days:     wds,
+		Start:    d.Start,
+		End:      d.End,
+		Duration: d.Duration,
+		Location: d.Location,
+	}, nil
+}
+
+// MarshalJSON implements json.Marshaler. The window is written as an object
+// such as {"start":"22:00","end":"02:00","weekdays":["mon","fri"],"timezone":"UTC"}.
+func (w TODWeekWindow) MarshalJSON() ([]byte, error) {
+	v, err := newTODWeekWindowJSON(w)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The fields are validated like
+// the arguments of ParseTODWeekWindow, and "weekdays" is required.
+func (w *TODWeekWindow) UnmarshalJSON(b []byte) error {
+	var v todWeekWindowJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	return w.set(v)
+}
+
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2 and
+// gopkg.in/yaml.v3.
+func (w TODWeekWindow) MarshalYAML() (interface{}, error) {
+	return newTODWeekWindowJSON(w)
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2,
+// which gopkg.in/yaml.v3 also supports.
+func (w *TODWeekWindow) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v todWeekWindowJSON
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	return w.set(v)
+}
+
+// set sets w to the window parsed from v.
+func (w *TODWeekWindow) set(v todWeekWindowJSON) error {
+	parsed, err := v.window()
+	if err != nil {
+		return err
+	}
+	*w = *parsed
+	return nil
+}
+
+// TODWeekWindow is a window that is open from Start to End on the weekdays in
+// Days.
 type TODWeekWindow struct {
+	// Weekdays are the weekdays that the window starts on if Days is empty.
+	// Its promoted NextDayOfWeek, DaysUntilNextDayOfWeek and Set methods are
+	// shadowed by methods of TODWeekWindow that use Days.
+	//
+	// Deprecated: Set Days instead. Weekdays is ignored if Days is not empty,
+	// and the constructors and parsers of this package only set Days.
 	Weekdays
 	Start TOD
 	End   TOD
+
+	// Days are the weekdays that the window starts on.
+	Days WeekdaySet
+
+	// Duration is the length of the window. If set, End is ignored and the
+	// window can span multiple days.
+	Duration time.Duration
+
+	// Location is the time zone that Start, End and Days are expressed in.
+	// If nil, the window is resolved in the location of the time that is
+	// passed in.
+	Location *time.Location
 }
 
+var _ Window = &TODWeekWindow{}
+
 // WithinWindow returns true if within the window. It also returns the time until
-// the next window.
+// the next window. A window without weekdays is never open and reports Never as
+// the time until it starts and since it started and ended.
 func (w *TODWeekWindow) WithinWindow(now time.Time) WindowResult {
-	return WithinWindow(now, w.StartTime(now), w.EndTime(now), w.FollowingStartTime(now))
+	if w.days().Len() == 0 {
+		return WindowResult{TTStart: Never, TSStart: Never, TSEnd: Never}
+	}
+	r := WithinWindow(now, w.StartTime(now), w.EndTime(now), w.FollowingStartTime(now))
+	return withTimeSince(r, w, now)
 }
 
+// StartTime returns the start of the window that now falls within. If now is
+// not within a window, the start of the window on today or the next matching
+// weekday is returned. Windows that cross midnight are anchored to the previous
+// day while they are still open. It returns the zero time if the window has no
+// weekdays.
 func (w *TODWeekWindow) StartTime(now time.Time) time.Time {
-	return w.accountForWeekday(time.Date(now.Year(), now.Month(), now.Day(), w.Start.Hour, w.Start.Minute, 0, 0, now.Location()))
+	if w.days().Len() == 0 {
+		return time.Time{}
+	}
+	loc := windowLocation(w.Location, now)
+	return wallClock(w.startDay(now, loc), w.Start, loc)
 }
 
+// FollowingStartTime returns the start of the window after the one returned by
+// StartTime. It returns the zero time if the window has no weekdays.
 func (w *TODWeekWindow) FollowingStartTime(now time.Time) time.Time {
-	return w.accountForWeekday(w.StartTime(now).Add(24 * time.Hour))
+	if w.days().Len() == 0 {
+		return time.Time{}
+	}
+	loc := windowLocation(w.Location, now)
+	return wallClock(w.accountForWeekday(w.startDay(now, loc).AddDate(0, 0, 1)), w.Start, loc)
 }
 
+// EndTime returns the end of the window returned by StartTime. It returns the
+// zero time if the window has no weekdays.
 func (w *TODWeekWindow) EndTime(now time.Time) time.Time {
-	end := w.accountForWeekday(time.Date(now.Year(), now.Month(), now.Day(), w.End.Hour, w.End.Minute, 0, 0, now.Location()))
+	if w.days().Len() == 0 {
+		return time.Time{}
+	}
+	loc := windowLocation(w.Location, now)
+	return w.endTime(w.startDay(now, loc), loc)
+}
 
-	if !w.sameDay() {
-		end = end.Add(24 * time.Hour)
+// PreviousStartTime returns the start of the most recent window that started
+// at or before now. It returns the zero time if there is no such window.
+func (w *TODWeekWindow) PreviousStartTime(now time.Time) time.Time {
+	start, _ := previousStartTime(w, now)
+	return start
+}
+
+// PreviousEndTime returns the end of the most recent window that ended at or
+// before now. It returns the zero time if there is no such window.
+func (w *TODWeekWindow) PreviousEndTime(now time.Time) time.Time {
+	end, _ := previousEndTime(w, now)
+	return end
+}
+
+// NextOccurrence returns the first window that starts after t on a matching
+// weekday.
+func (w *TODWeekWindow) NextOccurrence(t time.Time) (Interval, bool) {
+	loc := windowLocation(w.Location, t)
+	days := w.days()
+	day := date(t.In(loc)).AddDate(0, 0, -1)
+	for i := 0; i <= 8; i++ {
+		if days.Contains(day.Weekday()) {
+			if o := w.occurrence(day, loc); o.Start.After(t) {
+				return o, true
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return Interval{}, false
+}
+
+// PreviousOccurrence returns the last window that starts at or before t on a
+// matching weekday.
+func (w *TODWeekWindow) PreviousOccurrence(t time.Time) (Interval, bool) {
+	loc := windowLocation(w.Location, t)
+	days := w.days()
+	day := date(t.In(loc)).AddDate(0, 0, 1)
+	for i := 0; i <= 8; i++ {
+		if days.Contains(day.Weekday()) {
+			if o := w.occurrence(day, loc); !o.Start.After(t) {
+				return o, true
+			}
+		}
+		day = day.AddDate(0, 0, -1)
 	}
+	return Interval{}, false
+}
+
+// Occurrences returns the windows that overlap the range from from (inclusive)
+// to to (exclusive).
+func (w *TODWeekWindow) Occurrences(from, to time.Time) []Interval {
+	return occurrences(w, from, to)
+}
+
+// Iterate returns an iterator over the windows, beginning with the first one
+// that ends after from.
+func (w *TODWeekWindow) Iterate(from time.Time) *OccurrenceIterator {
+	return newOccurrenceIterator(w, from)
+}
+
+// occurrence returns the window that starts on day.
+func (w *TODWeekWindow) occurrence(day time.Time, loc *time.Location) Interval {
+	return Interval{Start: wallClock(day, w.Start, loc), End: w.endTime(day, loc)}
+}
 
+// startDay returns the calendar date that the window returned by StartTime
+// starts on.
+func (w *TODWeekWindow) startDay(now time.Time, loc *time.Location) time.Time {
+	days := w.days()
+	today := date(now.In(loc))
+	if days.Contains(today.Weekday()) && w.occurrence(today, loc).Contains(now) {
+		return today
+	}
+	// Overnight and multi-day windows may still be open from an earlier day.
+	// The latest one is used if they overlap.
+	for i := 1; i <= w.spanDays(); i++ {
+		day := today.AddDate(0, 0, -i)
+		if days.Contains(day.Weekday()) && w.occurrence(day, loc).Contains(now) {
+			return day
+		}
+	}
+	return w.accountForWeekday(today)
+}
+
+// endTime returns the end of the window that starts on day. If a DST
+// transition moves the start past the end, the window is empty on that day.
+func (w *TODWeekWindow) endTime(day time.Time, loc *time.Location) time.Time {
+	start := wallClock(day, w.Start, loc)
+	if w.Duration > 0 {
+		return start.Add(w.Duration)
+	}
+	if !w.sameDay() {
+		day = day.AddDate(0, 0, 1)
+	}
+	end := wallClock(day, w.End, loc)
+	if end.Before(start) {
+		return start
+	}
 	return end
 }
 
-func (w *TODWeekWindow) accountForWeekday(t time.Time) time.Time {
-	if !w.Weekdays[t.Weekday()] {
-		addDays := w.Weekdays.DaysUntilNextDayOfWeek(t.Weekday())
-		t = t.Add(24 * time.Hour * time.Duration(addDays))
+// accountForWeekday moves day forward to the next matching weekday.
+func (w *TODWeekWindow) accountForWeekday(day time.Time) time.Time {
+	if days := w.days(); !days.Contains(day.Weekday()) {
+		day = day.AddDate(0, 0, days.DaysUntilNextDayOfWeek(day.Weekday()))
+	}
+	return day
+}
+
+// days returns the weekdays that the window starts on: Days, or the deprecated
+// Weekdays map converted to a set if Days is empty.
+func (w *TODWeekWindow) days() WeekdaySet {
+	if w.Days&AllWeekdays != 0 {
+		return w.Days
+	}
+	return w.Weekdays.Set()
+}
+
+// Set returns the weekdays that the window starts on.
+func (w TODWeekWindow) Set() WeekdaySet {
+	return w.days()
+}
+
+// NextDayOfWeek returns the next weekday after today that the window starts
+// on. It returns today if that is the only such weekday and -1 if there are
+// none.
+func (w TODWeekWindow) NextDayOfWeek(today time.Weekday) time.Weekday {
+	return w.days().NextDayOfWeek(today)
+}
+
+// DaysUntilNextDayOfWeek returns the number of days from today until the next
+// weekday that the window starts on. It returns 0 if there are none.
+func (w TODWeekWindow) DaysUntilNextDayOfWeek(today time.Weekday) int {
+	return w.days().DaysUntilNextDayOfWeek(today)
+}
+
+// spanDays returns how many days after its start day a window can still be
+// open.
+func (w *TODWeekWindow) spanDays() int {
+	if w.Duration > 0 {
+		return int(w.Duration/(24*time.Hour)) + 1
+	}
+	if !w.sameDay() {
+		return 1
 	}
-	return t
+	return 0
 }
 
 func (w *TODWeekWindow) sameDay() bool {
-	return 60*w.Start.Hour+w.Start.Minute <= 60*w.End.Hour+w.End.Minute
+	return !w.End.Before(w.Start)
 }
@@ -1,6 +1,7 @@
 package timewindow
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -198,6 +199,183 @@ func TestTODWeekWindow(t *testing.T) {
 			endTime:              time.Date(2000, time.January, 1, 20, 0, 0, 0, time.UTC),
 			followingStartTime:   time.Date(2000, time.January, 8, 10, 0, 0, 0, time.UTC),
 		},
+		{
+			name: "overnight-one-hour-before-start",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+				Weekdays: Weekdays{
+					time.Saturday: true,
+				},
+			},
+			now:        time.Date(2000, time.January, 1, 21, 0, 0, 0, time.UTC),
+			nowWeekday: time.Saturday,
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: time.Hour,
+				TTEnd:   0,
+			},
+			resultTTWindowChange: time.Hour,
+			startTime:            time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+			endTime:              time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+			followingStartTime:   time.Date(2000, time.January, 8, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "overnight-on-start",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+				Weekdays: Weekdays{
+					time.Saturday: true,
+				},
+			},
+			now:        time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+			nowWeekday: time.Saturday,
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 0,
+				TTEnd:   4 * time.Hour,
+			},
+			resultTTWindowChange: 4 * time.Hour,
+			startTime:            time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+			endTime:              time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+			followingStartTime:   time.Date(2000, time.January, 8, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "overnight-on-midnight",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+				Weekdays: Weekdays{
+					time.Saturday: true,
+				},
+			},
+			now:        time.Date(2000, time.January, 2, 0, 0, 0, 0, time.UTC),
+			nowWeekday: time.Sunday,
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 24*6*time.Hour + 22*time.Hour,
+				TTEnd:   2 * time.Hour,
+			},
+			resultTTWindowChange: 2 * time.Hour,
+			startTime:            time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+			endTime:              time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+			followingStartTime:   time.Date(2000, time.January, 8, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "overnight-one-hour-after-midnight",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+				Weekdays: Weekdays{
+					time.Saturday: true,
+				},
+			},
+			now:        time.Date(2000, time.January, 2, 1, 0, 0, 0, time.UTC),
+			nowWeekday: time.Sunday,
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 24*6*time.Hour + 21*time.Hour,
+				TTEnd:   time.Hour,
+			},
+			resultTTWindowChange: time.Hour,
+			startTime:            time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+			endTime:              time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+			followingStartTime:   time.Date(2000, time.January, 8, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "overnight-on-end",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+				Weekdays: Weekdays{
+					time.Saturday: true,
+				},
+			},
+			now:        time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+			nowWeekday: time.Sunday,
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 24*6*time.Hour + 20*time.Hour,
+				TTEnd:   0,
+			},
+			resultTTWindowChange: 24*6*time.Hour + 20*time.Hour,
+			startTime:            time.Date(2000, time.January, 8, 22, 0, 0, 0, time.UTC),
+			endTime:              time.Date(2000, time.January, 9, 2, 0, 0, 0, time.UTC),
+			followingStartTime:   time.Date(2000, time.January, 15, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "overnight-one-hour-after-end",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+				Weekdays: Weekdays{
+					time.Saturday: true,
+				},
+			},
+			now:        time.Date(2000, time.January, 2, 3, 0, 0, 0, time.UTC),
+			nowWeekday: time.Sunday,
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 24*6*time.Hour + 19*time.Hour,
+				TTEnd:   0,
+			},
+			resultTTWindowChange: 24*6*time.Hour + 19*time.Hour,
+			startTime:            time.Date(2000, time.January, 8, 22, 0, 0, 0, time.UTC),
+			endTime:              time.Date(2000, time.January, 9, 2, 0, 0, 0, time.UTC),
+			followingStartTime:   time.Date(2000, time.January, 15, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "overnight-after-midnight-previous-day-not-selected",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+				Weekdays: Weekdays{
+					time.Sunday: true,
+				},
+			},
+			now:        time.Date(2000, time.January, 2, 1, 0, 0, 0, time.UTC),
+			nowWeekday: time.Sunday,
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 21 * time.Hour,
+				TTEnd:   0,
+			},
+			resultTTWindowChange: 21 * time.Hour,
+			startTime:            time.Date(2000, time.January, 2, 22, 0, 0, 0, time.UTC),
+			endTime:              time.Date(2000, time.January, 3, 2, 0, 0, 0, time.UTC),
+			followingStartTime:   time.Date(2000, time.January, 9, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "overnight-after-midnight-both-days-selected",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+				Weekdays: Weekdays{
+					time.Saturday: true,
+					time.Sunday:   true,
+				},
+			},
+			now:        time.Date(2000, time.January, 2, 1, 0, 0, 0, time.UTC),
+			nowWeekday: time.Sunday,
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 21 * time.Hour,
+				TTEnd:   time.Hour,
+			},
+			resultTTWindowChange: time.Hour,
+			startTime:            time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+			endTime:              time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+			followingStartTime:   time.Date(2000, time.January, 2, 22, 0, 0, 0, time.UTC),
+		},
 	}
 
 	for _, c := range cases {
@@ -216,3 +394,672 @@ func TestTODWeekWindow(t *testing.T) {
 		})
 	}
 }
+
+func TestTODWeekWindowDST(t *testing.T) {
+	cases := []struct {
+		name string
+
+		window TODWeekWindow
+		now    time.Time
+
+		result             WindowResult
+		startTime          time.Time
+		endTime            time.Time
+		followingStartTime time.Time
+	}{
+		{
+			name: "next-weekday-across-spring-forward",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 10, Minute: 0},
+				End:   TOD{Hour: 12, Minute: 0},
+				Weekdays: Weekdays{
+					time.Monday: true,
+				},
+			},
+			now: time.Date(2021, time.March, 12, 10, 0, 0, 0, newYork),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 3*24*time.Hour - time.Hour,
+				TTEnd:   0,
+			},
+			startTime:          time.Date(2021, time.March, 15, 10, 0, 0, 0, newYork),
+			endTime:            time.Date(2021, time.March, 15, 12, 0, 0, 0, newYork),
+			followingStartTime: time.Date(2021, time.March, 22, 10, 0, 0, 0, newYork),
+		},
+		{
+			name: "within-before-fall-back",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 10, Minute: 0},
+				End:   TOD{Hour: 12, Minute: 0},
+				Weekdays: Weekdays{
+					time.Saturday: true,
+				},
+			},
+			now: time.Date(2021, time.November, 6, 11, 0, 0, 0, newYork),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 7 * 24 * time.Hour,
+				TTEnd:   time.Hour,
+			},
+			startTime:          time.Date(2021, time.November, 6, 10, 0, 0, 0, newYork),
+			endTime:            time.Date(2021, time.November, 6, 12, 0, 0, 0, newYork),
+			followingStartTime: time.Date(2021, time.November, 13, 10, 0, 0, 0, newYork),
+		},
+		{
+			name: "overnight-across-spring-forward",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 6, Minute: 0},
+				Weekdays: Weekdays{
+					time.Saturday: true,
+				},
+			},
+			now: time.Date(2021, time.March, 14, 1, 0, 0, 0, newYork),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 7*24*time.Hour - 4*time.Hour,
+				TTEnd:   4 * time.Hour,
+			},
+			startTime:          time.Date(2021, time.March, 13, 22, 0, 0, 0, newYork),
+			endTime:            time.Date(2021, time.March, 14, 6, 0, 0, 0, newYork),
+			followingStartTime: time.Date(2021, time.March, 20, 22, 0, 0, 0, newYork),
+		},
+		{
+			name: "start-in-skipped-hour",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 2, Minute: 30},
+				End:   TOD{Hour: 4, Minute: 0},
+				Weekdays: Weekdays{
+					time.Sunday: true,
+				},
+			},
+			now: time.Date(2021, time.March, 13, 12, 0, 0, 0, newYork),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 14*time.Hour + 30*time.Minute,
+				TTEnd:   0,
+			},
+			startTime:          time.Date(2021, time.March, 14, 3, 30, 0, 0, newYork),
+			endTime:            time.Date(2021, time.March, 14, 4, 0, 0, 0, newYork),
+			followingStartTime: time.Date(2021, time.March, 21, 2, 30, 0, 0, newYork),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.startTime.String(), c.window.StartTime(c.now).String())
+			require.Equal(t, c.endTime.String(), c.window.EndTime(c.now).String())
+			require.Equal(t, c.followingStartTime.String(), c.window.FollowingStartTime(c.now).String())
+
+			result := c.window.WithinWindow(c.now)
+			require.Equal(t, c.result.Within, result.Within)
+			require.Equal(t, c.result.TTStart.String(), result.TTStart.String())
+			require.Equal(t, c.result.TTEnd.String(), result.TTEnd.String())
+		})
+	}
+}
+
+func TestTODWeekWindowLocation(t *testing.T) {
+	window := TODWeekWindow{
+		Start: TOD{Hour: 2, Minute: 0},
+		End:   TOD{Hour: 4, Minute: 0},
+		Weekdays: Weekdays{
+			time.Sunday: true,
+		},
+		Location: berlin,
+	}
+
+	cases := []struct {
+		name string
+		now  time.Time
+
+		result    WindowResult
+		startTime time.Time
+		endTime   time.Time
+	}{
+		{
+			name: "utc-saturday-before-sunday-window",
+			now:  time.Date(2021, time.June, 5, 23, 30, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 30 * time.Minute,
+				TTEnd:   0,
+			},
+			startTime: time.Date(2021, time.June, 6, 2, 0, 0, 0, berlin),
+			endTime:   time.Date(2021, time.June, 6, 4, 0, 0, 0, berlin),
+		},
+		{
+			name: "utc-sunday-within-window",
+			now:  time.Date(2021, time.June, 6, 0, 30, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 7*24*time.Hour - 30*time.Minute,
+				TTEnd:   time.Hour + 30*time.Minute,
+			},
+			startTime: time.Date(2021, time.June, 6, 2, 0, 0, 0, berlin),
+			endTime:   time.Date(2021, time.June, 6, 4, 0, 0, 0, berlin),
+		},
+		{
+			name: "utc-sunday-after-window",
+			now:  time.Date(2021, time.June, 6, 3, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 7*24*time.Hour - 3*time.Hour,
+				TTEnd:   0,
+			},
+			startTime: time.Date(2021, time.June, 6, 2, 0, 0, 0, berlin),
+			endTime:   time.Date(2021, time.June, 6, 4, 0, 0, 0, berlin),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.startTime.String(), window.StartTime(c.now).String())
+			require.Equal(t, c.endTime.String(), window.EndTime(c.now).String())
+
+			result := window.WithinWindow(c.now)
+			require.Equal(t, c.result.Within, result.Within)
+			require.Equal(t, c.result.TTStart.String(), result.TTStart.String())
+			require.Equal(t, c.result.TTEnd.String(), result.TTEnd.String())
+		})
+	}
+}
+
+func TestTODWeekWindowDuration(t *testing.T) {
+	weekend := NewTODWeekWindowFor(TOD{Hour: 22}, 56*time.Hour, NewWeekdaySet(time.Friday))
+
+	cases := []struct {
+		name string
+
+		window *TODWeekWindow
+		now    time.Time
+
+		result             WindowResult
+		startTime          time.Time
+		endTime            time.Time
+		followingStartTime time.Time
+	}{
+		{
+			name:   "before",
+			window: weekend,
+			now:    time.Date(2021, time.June, 4, 21, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: time.Hour,
+			},
+			startTime:          time.Date(2021, time.June, 4, 22, 0, 0, 0, time.UTC),
+			endTime:            time.Date(2021, time.June, 7, 6, 0, 0, 0, time.UTC),
+			followingStartTime: time.Date(2021, time.June, 11, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "within-two-days-later",
+			window: weekend,
+			now:    time.Date(2021, time.June, 6, 12, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 5*24*time.Hour + 10*time.Hour,
+				TTEnd:   18 * time.Hour,
+			},
+			startTime:          time.Date(2021, time.June, 4, 22, 0, 0, 0, time.UTC),
+			endTime:            time.Date(2021, time.June, 7, 6, 0, 0, 0, time.UTC),
+			followingStartTime: time.Date(2021, time.June, 11, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "after",
+			window: weekend,
+			now:    time.Date(2021, time.June, 7, 7, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 4*24*time.Hour + 15*time.Hour,
+			},
+			startTime:          time.Date(2021, time.June, 11, 22, 0, 0, 0, time.UTC),
+			endTime:            time.Date(2021, time.June, 14, 6, 0, 0, 0, time.UTC),
+			followingStartTime: time.Date(2021, time.June, 18, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "elapsed-across-fall-back",
+			window: weekend,
+			now:    time.Date(2021, time.November, 7, 12, 0, 0, 0, newYork),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 5*24*time.Hour + 10*time.Hour,
+				TTEnd:   17 * time.Hour,
+			},
+			startTime:          time.Date(2021, time.November, 5, 22, 0, 0, 0, newYork),
+			endTime:            time.Date(2021, time.November, 8, 5, 0, 0, 0, newYork),
+			followingStartTime: time.Date(2021, time.November, 12, 22, 0, 0, 0, newYork),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.startTime.String(), c.window.StartTime(c.now).String())
+			require.Equal(t, c.endTime.String(), c.window.EndTime(c.now).String())
+			require.Equal(t, c.followingStartTime.String(), c.window.FollowingStartTime(c.now).String())
+
+			result := c.window.WithinWindow(c.now)
+			require.Equal(t, c.result.Within, result.Within)
+			require.Equal(t, c.result.TTStart.String(), result.TTStart.String())
+			require.Equal(t, c.result.TTEnd.String(), result.TTEnd.String())
+		})
+	}
+}
+
+func TestTODWeekWindowAllDay(t *testing.T) {
+	weekend := NewAllDayWindow(NewWeekdaySet(time.Saturday, time.Sunday))
+
+	cases := []struct {
+		name string
+
+		window *TODWeekWindow
+		now    time.Time
+
+		result             WindowResult
+		startTime          time.Time
+		endTime            time.Time
+		followingStartTime time.Time
+	}{
+		{
+			name:   "before",
+			window: weekend,
+			now:    time.Date(2021, time.June, 4, 23, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: time.Hour,
+			},
+			startTime:          time.Date(2021, time.June, 5, 0, 0, 0, 0, time.UTC),
+			endTime:            time.Date(2021, time.June, 6, 0, 0, 0, 0, time.UTC),
+			followingStartTime: time.Date(2021, time.June, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "end-of-saturday",
+			window: weekend,
+			now:    time.Date(2021, time.June, 5, 23, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: time.Hour,
+				TTEnd:   time.Hour,
+			},
+			startTime:          time.Date(2021, time.June, 5, 0, 0, 0, 0, time.UTC),
+			endTime:            time.Date(2021, time.June, 6, 0, 0, 0, 0, time.UTC),
+			followingStartTime: time.Date(2021, time.June, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "start-of-sunday",
+			window: weekend,
+			now:    time.Date(2021, time.June, 6, 0, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 0,
+				TTEnd:   24 * time.Hour,
+			},
+			startTime:          time.Date(2021, time.June, 6, 0, 0, 0, 0, time.UTC),
+			endTime:            time.Date(2021, time.June, 7, 0, 0, 0, 0, time.UTC),
+			followingStartTime: time.Date(2021, time.June, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "short-dst-day",
+			window: NewAllDayWindow(NewWeekdaySet(time.Sunday)),
+			now:    time.Date(2021, time.March, 14, 12, 0, 0, 0, newYork),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 6*24*time.Hour + 12*time.Hour,
+				TTEnd:   12 * time.Hour,
+			},
+			startTime:          time.Date(2021, time.March, 14, 0, 0, 0, 0, newYork),
+			endTime:            time.Date(2021, time.March, 15, 0, 0, 0, 0, newYork),
+			followingStartTime: time.Date(2021, time.March, 21, 0, 0, 0, 0, newYork),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.startTime.String(), c.window.StartTime(c.now).String())
+			require.Equal(t, c.endTime.String(), c.window.EndTime(c.now).String())
+			require.Equal(t, c.followingStartTime.String(), c.window.FollowingStartTime(c.now).String())
+
+			result := c.window.WithinWindow(c.now)
+			require.Equal(t, c.result.Within, result.Within)
+			require.Equal(t, c.result.TTStart.String(), result.TTStart.String())
+			require.Equal(t, c.result.TTEnd.String(), result.TTEnd.String())
+		})
+	}
+}
+
+func TestParseTODWeekWindowEndOfDay(t *testing.T) {
+	w, err := ParseTODWeekWindow("18:00", "24:00", []string{"fri"})
+	require.NoError(t, err)
+	require.Equal(t, EndOfDay, w.End)
+
+	_, err = ParseTODWeekWindow("24:00", "02:00", []string{"fri"})
+	require.EqualError(t, err, "start: 24:00 is only valid as the end of a window")
+}
+
+func TestParseTODWeekWindowFor(t *testing.T) {
+	w, err := ParseTODWeekWindowFor("22:00", "56h", []string{"fri"})
+	require.NoError(t, err)
+	require.Equal(t, TOD{Hour: 22}, w.Start)
+	require.Equal(t, 56*time.Hour, w.Duration)
+	require.Equal(t, NewWeekdaySet(time.Friday), w.Days)
+
+	_, err = ParseTODWeekWindowFor("22:00", "-1h", []string{"fri"})
+	require.EqualError(t, err, "duration: must be positive")
+
+	_, err = ParseTODWeekWindowFor("22:00", "56h", []string{"freeday"})
+	require.Contains(t, err.Error(), "weekdays")
+}
+
+func TestParseTODWeekWindowInLocation(t *testing.T) {
+	w, err := ParseTODWeekWindowInLocation("02:00", "04:00", []string{"sun"}, "Europe/Berlin")
+	require.NoError(t, err)
+	require.Equal(t, NewWeekdaySet(time.Sunday), w.Days)
+	require.Equal(t, "Europe/Berlin", w.Location.String())
+
+	_, err = ParseTODWeekWindowInLocation("02:00", "04:00", []string{"sun"}, "Not/A_Zone")
+	require.Contains(t, err.Error(), "location")
+}
+
+func TestTODWeekWindowOccurrence(t *testing.T) {
+	cases := []struct {
+		name string
+
+		window TODWeekWindow
+		t      time.Time
+
+		next       Interval
+		nextOK     bool
+		previous   Interval
+		previousOK bool
+	}{
+		{
+			name: "between-weekdays",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 10, Minute: 0},
+				End:   TOD{Hour: 20, Minute: 0},
+				Weekdays: Weekdays{
+					time.Monday:   true,
+					time.Thursday: true,
+				},
+			},
+			t: time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC),
+
+			next: Interval{
+				Start: time.Date(2000, time.January, 3, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 3, 20, 0, 0, 0, time.UTC),
+			},
+			nextOK: true,
+			previous: Interval{
+				Start: time.Date(1999, time.December, 30, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(1999, time.December, 30, 20, 0, 0, 0, time.UTC),
+			},
+			previousOK: true,
+		},
+		{
+			name: "within-only-weekday",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 10, Minute: 0},
+				End:   TOD{Hour: 20, Minute: 0},
+				Weekdays: Weekdays{
+					time.Saturday: true,
+				},
+			},
+			t: time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC),
+
+			next: Interval{
+				Start: time.Date(2000, time.January, 8, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 8, 20, 0, 0, 0, time.UTC),
+			},
+			nextOK: true,
+			previous: Interval{
+				Start: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 20, 0, 0, 0, time.UTC),
+			},
+			previousOK: true,
+		},
+		{
+			name: "overnight-after-midnight",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+				Weekdays: Weekdays{
+					time.Saturday: true,
+				},
+			},
+			t: time.Date(2000, time.January, 2, 1, 0, 0, 0, time.UTC),
+
+			next: Interval{
+				Start: time.Date(2000, time.January, 8, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 9, 2, 0, 0, 0, time.UTC),
+			},
+			nextOK: true,
+			previous: Interval{
+				Start: time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+			},
+			previousOK: true,
+		},
+		{
+			name: "no-weekdays",
+			window: TODWeekWindow{
+				Start:    TOD{Hour: 10, Minute: 0},
+				End:      TOD{Hour: 20, Minute: 0},
+				Weekdays: Weekdays{},
+			},
+			t: time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			next, ok := c.window.NextOccurrence(c.t)
+			require.Equal(t, c.nextOK, ok)
+			require.Equal(t, c.next.Start.String(), next.Start.String())
+			require.Equal(t, c.next.End.String(), next.End.String())
+
+			previous, ok := c.window.PreviousOccurrence(c.t)
+			require.Equal(t, c.previousOK, ok)
+			require.Equal(t, c.previous.Start.String(), previous.Start.String())
+			require.Equal(t, c.previous.End.String(), previous.End.String())
+		})
+	}
+}
+
+func TestTODWeekWindowPrevious(t *testing.T) {
+	cases := []struct {
+		name string
+
+		window TODWeekWindow
+		now    time.Time
+
+		previousStartTime time.Time
+		previousEndTime   time.Time
+		tsStart           time.Duration
+		tsEnd             time.Duration
+	}{
+		{
+			name: "between-weekdays",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 10, Minute: 0},
+				End:   TOD{Hour: 20, Minute: 0},
+				Weekdays: Weekdays{
+					time.Thursday: true,
+				},
+			},
+			now: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+
+			previousStartTime: time.Date(1999, time.December, 30, 10, 0, 0, 0, time.UTC),
+			previousEndTime:   time.Date(1999, time.December, 30, 20, 0, 0, 0, time.UTC),
+			tsStart:           2 * 24 * time.Hour,
+			tsEnd:             24*time.Hour + 14*time.Hour,
+		},
+		{
+			name: "within-window",
+			window: TODWeekWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+				Weekdays: Weekdays{
+					time.Saturday: true,
+				},
+			},
+			now: time.Date(2000, time.January, 2, 1, 0, 0, 0, time.UTC),
+
+			previousStartTime: time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+			previousEndTime:   time.Date(1999, time.December, 26, 2, 0, 0, 0, time.UTC),
+			tsStart:           3 * time.Hour,
+			tsEnd:             7*24*time.Hour - time.Hour,
+		},
+		{
+			name: "no-weekdays",
+			window: TODWeekWindow{
+				Start:    TOD{Hour: 10, Minute: 0},
+				End:      TOD{Hour: 20, Minute: 0},
+				Weekdays: Weekdays{},
+			},
+			now: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+
+			tsStart: Never,
+			tsEnd:   Never,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.previousStartTime.String(), c.window.PreviousStartTime(c.now).String())
+			require.Equal(t, c.previousEndTime.String(), c.window.PreviousEndTime(c.now).String())
+
+			result := c.window.WithinWindow(c.now)
+			require.Equal(t, c.tsStart.String(), result.TSStart.String())
+			require.Equal(t, c.tsEnd.String(), result.TSEnd.String())
+		})
+	}
+}
+
+func TestTODWeekWindowJSON(t *testing.T) {
+	w := TODWeekWindow{
+		Start:    TOD{Hour: 22},
+		End:      TOD{Hour: 2},
+		Days:     NewWeekdaySet(time.Monday, time.Friday),
+		Location: time.UTC,
+	}
+
+	b, err := json.Marshal(w)
+	require.NoError(t, err)
+	require.Equal(t, `{"start":"22:00","end":"02:00","weekdays":["mon","fri"],"timezone":"UTC"}`, string(b))
+
+	var parsed TODWeekWindow
+	require.NoError(t, json.Unmarshal(b, &parsed))
+	require.Equal(t, w, parsed)
+
+	v, err := w.MarshalYAML()
+	require.NoError(t, err)
+	b, err = json.Marshal(v)
+	require.NoError(t, err)
+	parsed = TODWeekWindow{}
+	require.NoError(t, parsed.UnmarshalYAML(func(v interface{}) error {
+		return json.Unmarshal(b, v)
+	}))
+	require.Equal(t, w, parsed)
+
+	err = json.Unmarshal([]byte(`{"start":"22:00","end":"02:00","weekdays":["mon","funday"]}`), &parsed)
+	require.EqualError(t, err, "weekdays: unrecognized weekday: funday")
+
+	err = json.Unmarshal([]byte(`{"start":"22:00","end":"2am","weekdays":["mon"]}`), &parsed)
+	require.EqualError(t, err, "end: invalid format (expected 12:34 or 12:34:56): 2am")
+
+	err = json.Unmarshal([]byte(`{"start":"22:00","end":"02:00"}`), &parsed)
+	require.EqualError(t, err, "missing weekdays")
+
+	err = json.Unmarshal([]byte(`{"start":"22:00","end":"02:00","weekdays":null}`), &parsed)
+	require.EqualError(t, err, "missing weekdays")
+
+	parsed = TODWeekWindow{}
+	require.NoError(t, json.Unmarshal([]byte(`{"start":"22:00","end":"02:00","weekdays":[]}`), &parsed))
+	require.Equal(t, TODWeekWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 2}}, parsed)
+	require.NoError(t, json.Unmarshal([]byte(`{"start":"22:00","end":"02:00","weekdays":["none"]}`), &parsed))
+	require.Equal(t, TODWeekWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 2}}, parsed)
+
+	_, err = json.Marshal(TODWeekWindow{Days: AllWeekdays, Location: time.FixedZone("X", 3600)})
+	require.Contains(t, err.Error(), "timezone: \"X\" is not an IANA time zone name")
+
+	_, err = TODWeekWindow{Days: AllWeekdays, Location: time.FixedZone("X", 3600)}.MarshalText()
+	require.EqualError(t, err, "timezone: \"X\" is not an IANA time zone name")
+}
+
+func TestTODWeekWindowNever(t *testing.T) {
+	now := time.Date(2021, time.June, 7, 12, 0, 0, 0, time.UTC)
+
+	for name, weekdays := range map[string]Weekdays{
+		"empty":              {},
+		"only-false-entries": {time.Monday: false, time.Tuesday: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := TODWeekWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 14}, Weekdays: weekdays}
+
+			require.Equal(t, WindowResult{TTStart: Never, TSStart: Never, TSEnd: Never}, w.WithinWindow(now))
+			require.True(t, w.StartTime(now).IsZero())
+			require.True(t, w.EndTime(now).IsZero())
+			require.True(t, w.FollowingStartTime(now).IsZero())
+			_, ok := w.NextOccurrence(now)
+			require.False(t, ok)
+			_, ok = w.PreviousOccurrence(now)
+			require.False(t, ok)
+			require.Empty(t, w.Occurrences(now, now.AddDate(0, 0, 14)))
+		})
+	}
+}
+
+func TestTODWeekWindowText(t *testing.T) {
+	var w TODWeekWindow
+	require.NoError(t, w.UnmarshalText([]byte("Mon,Fri 22:00-02:00 UTC")))
+
+	text, err := w.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "Mon,Fri 22:00-02:00 UTC", string(text))
+
+	b, err := json.Marshal(w)
+	require.NoError(t, err)
+	require.Equal(t, `{"start":"22:00","end":"02:00","weekdays":["mon","fri"],"timezone":"UTC"}`, string(b))
+
+	require.EqualError(t, w.UnmarshalText([]byte("Mon,Funday 22:00-02:00")), "weekdays: unrecognized weekday: Funday")
+	require.EqualError(t, w.UnmarshalText([]byte("Mon 22:00-25:00")), "end: invalid hour: 25")
+}
+
+func TestTODWeekWindowDays(t *testing.T) {
+	now := time.Date(2021, time.June, 7, 12, 0, 0, 0, time.UTC) // Monday
+
+	withMap := &TODWeekWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 14}, Weekdays: Weekdays{time.Monday: true, time.Wednesday: true}}
+	withSet := &TODWeekWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 14}, Days: NewWeekdaySet(time.Monday, time.Wednesday)}
+	// The deprecated map is ignored if Days is set.
+	withBoth := &TODWeekWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 14}, Weekdays: Weekdays{time.Friday: true}, Days: NewWeekdaySet(time.Monday, time.Wednesday)}
+
+	for _, w := range []*TODWeekWindow{withMap, withSet, withBoth} {
+		require.Equal(t, WindowResult{Within: true, TTStart: 46 * time.Hour, TTEnd: 2 * time.Hour, TSStart: 2 * time.Hour, TSEnd: 118 * time.Hour}, w.WithinWindow(now))
+		require.Equal(t, "Mon,Wed 10:00-14:00", w.String())
+		require.Equal(t, NewWeekdaySet(time.Monday, time.Wednesday), w.Set())
+		require.Equal(t, time.Monday, w.NextDayOfWeek(time.Sunday))
+		require.Equal(t, 2, w.DaysUntilNextDayOfWeek(time.Monday))
+
+		parsed, err := ParseWindow(w.String())
+		require.NoError(t, err)
+		require.Equal(t, withSet, parsed)
+	}
+
+	b, err := json.Marshal(withSet)
+	require.NoError(t, err)
+	require.Equal(t, `{"start":"10:00","end":"14:00","weekdays":["mon","wed"]}`, string(b))
+}
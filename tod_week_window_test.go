@@ -1,6 +1,7 @@
 package timewindow_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"time"
@@ -21,3 +22,40 @@ func ExampleTODWeekWindow() {
 	fmt.Println("within: ", result.Within)
 	fmt.Println("untilStart: ", result.TTStart)
 }
+
+func ExampleTODWeekWindow_Occurrences() {
+	window, err := timewindow.ParseTODWeekWindow("22:00", "02:00", []string{"Tue", "Sat"})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	from := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	for _, o := range window.Occurrences(from, to) {
+		fmt.Println(o.Start.Format(time.RFC1123), "-", o.End.Format(time.RFC1123))
+	}
+	// Output:
+	// Sat, 01 Jan 2000 22:00:00 UTC - Sun, 02 Jan 2000 02:00:00 UTC
+	// Tue, 04 Jan 2000 22:00:00 UTC - Wed, 05 Jan 2000 02:00:00 UTC
+}
+
+func ExampleTODWeekWindow_UnmarshalJSON() {
+	var window timewindow.TODWeekWindow
+	err := json.Unmarshal([]byte(`{"start":"22:00","end":"02:00","weekdays":["mon","fri"],"timezone":"UTC"}`), &window)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	now := time.Date(2021, time.June, 7, 23, 0, 0, 0, time.UTC) // Monday
+	fmt.Println("within:", window.WithinWindow(now).Within)
+
+	b, err := json.Marshal(window)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(b))
+
+	// Output:
+	// within: true
+	// {"start":"22:00","end":"02:00","weekdays":["mon","fri"],"timezone":"UTC"}
+}
@@ -1,12 +1,14 @@
 package timewindow
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 )
 
 func ParseTODWindow(start, end string) (*TODWindow, error) {
-	s, err := ParseTOD(start)
+	s, err := parseStartTOD(start)
 	if err != nil {
 		return nil, fmt.Errorf("start: %w", err)
 	}
@@ -19,33 +21,318 @@ func ParseTODWindow(start, end string) (*TODWindow, error) {
 	return &TODWindow{Start: s, End: e}, nil
 }
 
+// ParseTODWindowInLocation is like ParseTODWindow but resolves the window in the
+// location with the given IANA name (for example "Europe/Berlin").
+func ParseTODWindowInLocation(start, end, location string) (*TODWindow, error) {
+	w, err := ParseTODWindow(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(location)
+	if err != nil {
+		return nil, fmt.Errorf("location: %w", err)
+	}
+	w.Location = loc
+
+	return w, nil
+}
+
+// NewTODWindowFor returns a daily window that starts at start and lasts for d.
+// It returns an error if d is 24h or longer, as the windows of consecutive days
+// would overlap. Use NewTODWeekWindowFor for longer windows.
+func NewTODWindowFor(start TOD, d time.Duration) (*TODWindow, error) {
+	if err := checkDailyDuration(d); err != nil {
+		return nil, fmt.Errorf("duration: %w", err)
+	}
+	return &TODWindow{Start: start, End: start, Duration: d}, nil
+}
+
+// ParseTODWindowFor is like ParseTODWindow but takes the length of the window
+// as a duration (for example "4h") instead of an end time. The duration must be
+// less than 24h.
+func ParseTODWindowFor(start, duration string) (*TODWindow, error) {
+	s, err := parseStartTOD(start)
+	if err != nil {
+		return nil, fmt.Errorf("start: %w", err)
+	}
+
+	d, err := parseWindowDuration(duration)
+	if err != nil {
+		return nil, fmt.Errorf("duration: %w", err)
+	}
+
+	return NewTODWindowFor(s, d)
+}
+
+// String returns the window in the form accepted by ParseWindow, such as
+// "22:00-02:00 America/Chicago". A Location that is not an IANA time zone,
+// such as one created with time.FixedZone, is written by name but cannot be
+// parsed back.
+func (w TODWindow) String() string {
+	return formatWindowSpec(w.Start, w.End, w.Duration, w.Location)
+}
+
+// MarshalText implements encoding.TextMarshaler using String. It returns an
+// error if Location is not an IANA time zone.
+func (w TODWindow) MarshalText() ([]byte, error) {
+	if _, err := timezoneName(w.Location); err != nil {
+		return nil, err
+	}
+	return []byte(w.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It parses a single window
+// without weekdays in the form accepted by ParseWindow.
+func (w *TODWindow) UnmarshalText(text []byte) error {
+	parsed, hasWeekdays, err := parseWeekWindowSpec(string(text))
+	if err != nil {
+		return err
+	}
+	if hasWeekdays {
+		return errors.New("unexpected weekdays in daily window: " + string(text))
+	}
+	*w = TODWindow{Start: parsed.Start, End: parsed.End, Duration: parsed.Duration, Location: parsed.Location}
+	return nil
+}
+
+// todWindowJSON is the JSON and YAML representation of a TODWindow.
+type todWindowJSON struct {
+	Start    string `json:"start" yaml:"start"`
+	End      string `json:"end,omitempty" yaml:"end,omitempty"`
+	Duration string `json:"duration,omitempty" yaml:"duration,omitempty"`
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
+
+func newTODWindowJSON(w TODWindow) (todWindowJSON, error) {
+	tz, err := timezoneName(w.Location)
+	if err != nil {
+		return todWindowJSON{}, err
+	}
+	v := todWindowJSON{Start: w.Start.String(), Timezone: tz}
+	if w.Duration > 0 {
+		v.Duration = w.Duration.String()
+	} else {
+		v.End = w.End.String()
+	}
+	return v, nil
+}
+
+// window parses v with the same validation as ParseTODWindow and
+// ParseTODWindowFor.
+func (v todWindowJSON) window() (*TODWindow, error) {
+	var (
+		w   *TODWindow
+		err error
+	)
+	switch {
+	case v.End != "" && v.Duration != "":
+		return nil, errors.New("end and duration are mutually exclusive")
+	case v.Duration != "":
+		w, err = ParseTODWindowFor(v.Start, v.Duration)
+	default:
+		w, err = ParseTODWindow(v.Start, v.End)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	w.Location, err = parseTimezone(v.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// MarshalJSON implements json.Marshaler. The window is written as an object
+// such as {"start":"22:00","end":"02:00","timezone":"UTC"}.
+func (w TODWindow) MarshalJSON() ([]byte, error) {
+	v, err := newTODWindowJSON(w)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The fields are validated like
+// the arguments of ParseTODWindow.
+func (w *TODWindow) UnmarshalJSON(b []byte) error {
+	var v todWindowJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	return w.set(v)
+}
+
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2 and
+// gopkg.in/yaml.v3.
+func (w TODWindow) MarshalYAML() (interface{}, error) {
+	return newTODWindowJSON(w)
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2,
+// which gopkg.in/yaml.v3 also supports.
+func (w *TODWindow) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v todWindowJSON
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	return w.set(v)
+}
+
+// set sets w to the window parsed from v.
+func (w *TODWindow) set(v todWindowJSON) error {
+	parsed, err := v.window()
+	if err != nil {
+		return err
+	}
+	*w = *parsed
+	return nil
+}
+
 type TODWindow struct {
 	Start TOD
 	End   TOD
+
+	// Duration is the length of the window. If set, End is ignored. It must be
+	// less than a day, as the windows of consecutive days would overlap
+	// otherwise; use a TODWeekWindow for windows that span multiple days.
+	Duration time.Duration
+
+	// Location is the time zone that Start and End are expressed in. If nil,
+	// the window is resolved in the location of the time that is passed in.
+	Location *time.Location
 }
 
+var _ Window = &TODWindow{}
+
 // WithinWindow returns true if within the window. It also returns the time until
 // the next window.
 func (w *TODWindow) WithinWindow(now time.Time) WindowResult {
-	return WithinWindow(now, w.StartTime(now), w.EndTime(now), w.FollowingStartTime(now))
+	r := WithinWindow(now, w.StartTime(now), w.EndTime(now), w.FollowingStartTime(now))
+	return withTimeSince(r, w, now)
 }
 
+// StartTime returns the start of the window that now falls within. If now is
+// not within a window, the start of today's window is returned. Windows that
+// cross midnight are anchored to the previous day while they are still open.
 func (w *TODWindow) StartTime(now time.Time) time.Time {
-	return time.Date(now.Year(), now.Month(), now.Day(), w.Start.Hour, w.Start.Minute, 0, 0, now.Location())
+	loc := windowLocation(w.Location, now)
+	return wallClock(w.startDay(now, loc), w.Start, loc)
 }
 
 func (w *TODWindow) FollowingStartTime(now time.Time) time.Time {
-	return time.Date(now.Year(), now.Month(), now.Day(), w.Start.Hour, w.Start.Minute, 0, 0, now.Location()).Add(24 * time.Hour)
+	loc := windowLocation(w.Location, now)
+	return wallClock(w.startDay(now, loc).AddDate(0, 0, 1), w.Start, loc)
 }
 
 func (w *TODWindow) EndTime(now time.Time) time.Time {
-	end := time.Date(now.Year(), now.Month(), now.Day(), w.End.Hour, w.End.Minute, 0, 0, now.Location())
+	loc := windowLocation(w.Location, now)
+	return w.endTime(w.startDay(now, loc), loc)
+}
+
+// PreviousStartTime returns the start of the most recent window that started
+// at or before now. It returns the zero time if there is no such window.
+func (w *TODWindow) PreviousStartTime(now time.Time) time.Time {
+	start, _ := previousStartTime(w, now)
+	return start
+}
+
+// PreviousEndTime returns the end of the most recent window that ended at or
+// before now. It returns the zero time if there is no such window.
+func (w *TODWindow) PreviousEndTime(now time.Time) time.Time {
+	end, _ := previousEndTime(w, now)
+	return end
+}
+
+// NextOccurrence returns the first window that starts after t.
+func (w *TODWindow) NextOccurrence(t time.Time) (Interval, bool) {
+	loc := windowLocation(w.Location, t)
+	day := date(t.In(loc)).AddDate(0, 0, -1)
+	for {
+		if o := w.occurrence(day, loc); o.Start.After(t) {
+			return o, true
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+}
+
+// PreviousOccurrence returns the last window that starts at or before t.
+func (w *TODWindow) PreviousOccurrence(t time.Time) (Interval, bool) {
+	loc := windowLocation(w.Location, t)
+	day := date(t.In(loc)).AddDate(0, 0, 1)
+	for {
+		if o := w.occurrence(day, loc); !o.Start.After(t) {
+			return o, true
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+}
+
+// Occurrences returns the windows that overlap the range from from (inclusive)
+// to to (exclusive).
+func (w *TODWindow) Occurrences(from, to time.Time) []Interval {
+	return occurrences(w, from, to)
+}
+
+// Iterate returns an iterator over the windows, beginning with the first one
+// that ends after from.
+func (w *TODWindow) Iterate(from time.Time) *OccurrenceIterator {
+	return newOccurrenceIterator(w, from)
+}
+
+// occurrence returns the window that starts on day.
+func (w *TODWindow) occurrence(day time.Time, loc *time.Location) Interval {
+	return Interval{Start: wallClock(day, w.Start, loc), End: w.endTime(day, loc)}
+}
+
+// startDay returns the calendar date that the window returned by StartTime
+// starts on.
+func (w *TODWindow) startDay(now time.Time, loc *time.Location) time.Time {
+	today := date(now.In(loc))
+	if w.occurrence(today, loc).Contains(now) {
+		return today
+	}
+	// Overnight and multi-day windows may still be open from an earlier day.
+	// The latest one is used if they overlap.
+	for i := 1; i <= w.spanDays(); i++ {
+		day := today.AddDate(0, 0, -i)
+		if w.occurrence(day, loc).Contains(now) {
+			return day
+		}
+	}
+	return today
+}
+
+// endTime returns the end of the window that starts on day. If a DST
+// transition moves the start past the end, the window is empty on that day.
+func (w *TODWindow) endTime(day time.Time, loc *time.Location) time.Time {
+	start := wallClock(day, w.Start, loc)
+	if w.Duration > 0 {
+		return start.Add(w.Duration)
+	}
 	if !w.sameDay() {
-		end = end.Add(24 * time.Hour)
+		day = day.AddDate(0, 0, 1)
+	}
+	end := wallClock(day, w.End, loc)
+	if end.Before(start) {
+		return start
 	}
 	return end
 }
 
+// spanDays returns how many days after its start day a window can still be
+// open.
+func (w *TODWindow) spanDays() int {
+	if w.Duration > 0 {
+		return int(w.Duration/(24*time.Hour)) + 1
+	}
+	if !w.sameDay() {
+		return 1
+	}
+	return 0
+}
+
 func (w *TODWindow) sameDay() bool {
-	return 60*w.Start.Hour+w.Start.Minute <= 60*w.End.Hour+w.End.Minute
+	return !w.End.Before(w.Start)
 }
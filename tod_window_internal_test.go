@@ -1,6 +1,7 @@
 package timewindow
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -211,6 +212,139 @@ func TestTODWindow(t *testing.T) {
 			endTime:              time.Date(2000, time.January, 1, 20, 0, 0, 0, time.UTC),
 			followingStartTime:   time.Date(2000, time.January, 2, 10, 0, 0, 0, time.UTC),
 		},
+		{
+			name: "overnight-one-hour-before-start",
+			window: TODWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+			},
+			now: time.Date(2000, time.January, 1, 21, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: time.Hour,
+				TTEnd:   0,
+			},
+			resultTTWindowChange: time.Hour,
+			sameDay:              false,
+			startTime:            time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+			endTime:              time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+			followingStartTime:   time.Date(2000, time.January, 2, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "overnight-on-start",
+			window: TODWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+			},
+			now: time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 0,
+				TTEnd:   4 * time.Hour,
+			},
+			resultTTWindowChange: 4 * time.Hour,
+			sameDay:              false,
+			startTime:            time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+			endTime:              time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+			followingStartTime:   time.Date(2000, time.January, 2, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "overnight-one-minute-before-midnight",
+			window: TODWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+			},
+			now: time.Date(2000, time.January, 1, 23, 59, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 22*time.Hour + time.Minute,
+				TTEnd:   2*time.Hour + time.Minute,
+			},
+			resultTTWindowChange: 2*time.Hour + time.Minute,
+			sameDay:              false,
+			startTime:            time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+			endTime:              time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+			followingStartTime:   time.Date(2000, time.January, 2, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "overnight-on-midnight",
+			window: TODWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+			},
+			now: time.Date(2000, time.January, 2, 0, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 22 * time.Hour,
+				TTEnd:   2 * time.Hour,
+			},
+			resultTTWindowChange: 2 * time.Hour,
+			sameDay:              false,
+			startTime:            time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+			endTime:              time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+			followingStartTime:   time.Date(2000, time.January, 2, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "overnight-one-hour-after-midnight",
+			window: TODWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+			},
+			now: time.Date(2000, time.January, 2, 1, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 21 * time.Hour,
+				TTEnd:   time.Hour,
+			},
+			resultTTWindowChange: time.Hour,
+			sameDay:              false,
+			startTime:            time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+			endTime:              time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+			followingStartTime:   time.Date(2000, time.January, 2, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "overnight-on-end",
+			window: TODWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+			},
+			now: time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 20 * time.Hour,
+				TTEnd:   0,
+			},
+			resultTTWindowChange: 20 * time.Hour,
+			sameDay:              false,
+			startTime:            time.Date(2000, time.January, 2, 22, 0, 0, 0, time.UTC),
+			endTime:              time.Date(2000, time.January, 3, 2, 0, 0, 0, time.UTC),
+			followingStartTime:   time.Date(2000, time.January, 3, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "overnight-one-hour-after-end",
+			window: TODWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+			},
+			now: time.Date(2000, time.January, 2, 3, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 19 * time.Hour,
+				TTEnd:   0,
+			},
+			resultTTWindowChange: 19 * time.Hour,
+			sameDay:              false,
+			startTime:            time.Date(2000, time.January, 2, 22, 0, 0, 0, time.UTC),
+			endTime:              time.Date(2000, time.January, 3, 2, 0, 0, 0, time.UTC),
+			followingStartTime:   time.Date(2000, time.January, 3, 22, 0, 0, 0, time.UTC),
+		},
 	}
 
 	for _, c := range cases {
@@ -228,3 +362,731 @@ func TestTODWindow(t *testing.T) {
 		})
 	}
 }
+
+func TestTODWindowDST(t *testing.T) {
+	cases := []struct {
+		name string
+
+		window TODWindow
+		now    time.Time
+
+		result             WindowResult
+		startTime          time.Time
+		endTime            time.Time
+		followingStartTime time.Time
+	}{
+		{
+			name: "overnight-across-spring-forward",
+			window: TODWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 6, Minute: 0},
+			},
+			now: time.Date(2021, time.March, 13, 23, 0, 0, 0, newYork),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 22 * time.Hour,
+				TTEnd:   6 * time.Hour,
+			},
+			startTime:          time.Date(2021, time.March, 13, 22, 0, 0, 0, newYork),
+			endTime:            time.Date(2021, time.March, 14, 6, 0, 0, 0, newYork),
+			followingStartTime: time.Date(2021, time.March, 14, 22, 0, 0, 0, newYork),
+		},
+		{
+			name: "overnight-across-fall-back",
+			window: TODWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 6, Minute: 0},
+			},
+			now: time.Date(2021, time.November, 6, 23, 0, 0, 0, newYork),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 24 * time.Hour,
+				TTEnd:   8 * time.Hour,
+			},
+			startTime:          time.Date(2021, time.November, 6, 22, 0, 0, 0, newYork),
+			endTime:            time.Date(2021, time.November, 7, 6, 0, 0, 0, newYork),
+			followingStartTime: time.Date(2021, time.November, 7, 22, 0, 0, 0, newYork),
+		},
+		{
+			name: "day-before-spring-forward",
+			window: TODWindow{
+				Start: TOD{Hour: 10, Minute: 0},
+				End:   TOD{Hour: 12, Minute: 0},
+			},
+			now: time.Date(2021, time.March, 13, 13, 0, 0, 0, newYork),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 20 * time.Hour,
+				TTEnd:   0,
+			},
+			startTime:          time.Date(2021, time.March, 13, 10, 0, 0, 0, newYork),
+			endTime:            time.Date(2021, time.March, 13, 12, 0, 0, 0, newYork),
+			followingStartTime: time.Date(2021, time.March, 14, 10, 0, 0, 0, newYork),
+		},
+		{
+			name: "start-in-skipped-hour",
+			window: TODWindow{
+				Start: TOD{Hour: 2, Minute: 30},
+				End:   TOD{Hour: 4, Minute: 0},
+			},
+			now: time.Date(2021, time.March, 14, 1, 0, 0, 0, newYork),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: time.Hour + 30*time.Minute,
+				TTEnd:   0,
+			},
+			startTime:          time.Date(2021, time.March, 14, 3, 30, 0, 0, newYork),
+			endTime:            time.Date(2021, time.March, 14, 4, 0, 0, 0, newYork),
+			followingStartTime: time.Date(2021, time.March, 15, 2, 30, 0, 0, newYork),
+		},
+		{
+			name: "start-and-end-in-skipped-hour",
+			window: TODWindow{
+				Start: TOD{Hour: 2, Minute: 10},
+				End:   TOD{Hour: 2, Minute: 50},
+			},
+			now: time.Date(2021, time.March, 14, 3, 20, 0, 0, newYork),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 23*time.Hour - 10*time.Minute,
+				TTEnd:   30 * time.Minute,
+			},
+			startTime:          time.Date(2021, time.March, 14, 3, 10, 0, 0, newYork),
+			endTime:            time.Date(2021, time.March, 14, 3, 50, 0, 0, newYork),
+			followingStartTime: time.Date(2021, time.March, 15, 2, 10, 0, 0, newYork),
+		},
+		{
+			name: "skipped-start-moved-past-end",
+			window: TODWindow{
+				Start: TOD{Hour: 2, Minute: 30},
+				End:   TOD{Hour: 3, Minute: 15},
+			},
+			now: time.Date(2021, time.March, 14, 3, 20, 0, 0, newYork),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 10 * time.Minute,
+				TTEnd:   0,
+			},
+			startTime:          time.Date(2021, time.March, 14, 3, 30, 0, 0, newYork),
+			endTime:            time.Date(2021, time.March, 14, 3, 30, 0, 0, newYork),
+			followingStartTime: time.Date(2021, time.March, 15, 2, 30, 0, 0, newYork),
+		},
+		{
+			name: "start-in-repeated-hour",
+			window: TODWindow{
+				Start: TOD{Hour: 1, Minute: 30},
+				End:   TOD{Hour: 3, Minute: 0},
+			},
+			now: time.Date(2021, time.November, 7, 0, 0, 0, 0, newYork),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: time.Hour + 30*time.Minute,
+				TTEnd:   0,
+			},
+			startTime:          time.Date(2021, time.November, 7, 5, 30, 0, 0, time.UTC).In(newYork),
+			endTime:            time.Date(2021, time.November, 7, 3, 0, 0, 0, newYork),
+			followingStartTime: time.Date(2021, time.November, 8, 1, 30, 0, 0, newYork),
+		},
+		{
+			name: "within-second-occurrence-of-repeated-hour",
+			window: TODWindow{
+				Start: TOD{Hour: 1, Minute: 30},
+				End:   TOD{Hour: 3, Minute: 0},
+			},
+			now: time.Date(2021, time.November, 7, 6, 45, 0, 0, time.UTC).In(newYork),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 24*time.Hour - 15*time.Minute,
+				TTEnd:   time.Hour + 15*time.Minute,
+			},
+			startTime:          time.Date(2021, time.November, 7, 5, 30, 0, 0, time.UTC).In(newYork),
+			endTime:            time.Date(2021, time.November, 7, 3, 0, 0, 0, newYork),
+			followingStartTime: time.Date(2021, time.November, 8, 1, 30, 0, 0, newYork),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.startTime.String(), c.window.StartTime(c.now).String())
+			require.Equal(t, c.endTime.String(), c.window.EndTime(c.now).String())
+			require.Equal(t, c.followingStartTime.String(), c.window.FollowingStartTime(c.now).String())
+
+			result := c.window.WithinWindow(c.now)
+			require.Equal(t, c.result.Within, result.Within)
+			require.Equal(t, c.result.TTStart.String(), result.TTStart.String())
+			require.Equal(t, c.result.TTEnd.String(), result.TTEnd.String())
+		})
+	}
+}
+
+func TestTODWindowLocation(t *testing.T) {
+	window := TODWindow{
+		Start:    TOD{Hour: 22, Minute: 0},
+		End:      TOD{Hour: 2, Minute: 0},
+		Location: berlin,
+	}
+
+	cases := []struct {
+		name string
+		now  time.Time
+
+		result    WindowResult
+		startTime time.Time
+		endTime   time.Time
+	}{
+		{
+			name: "utc-before-window",
+			now:  time.Date(2021, time.June, 1, 19, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: time.Hour,
+				TTEnd:   0,
+			},
+			startTime: time.Date(2021, time.June, 1, 22, 0, 0, 0, berlin),
+			endTime:   time.Date(2021, time.June, 2, 2, 0, 0, 0, berlin),
+		},
+		{
+			name: "utc-previous-day-within-window",
+			now:  time.Date(2021, time.June, 1, 23, 30, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 20*time.Hour + 30*time.Minute,
+				TTEnd:   30 * time.Minute,
+			},
+			startTime: time.Date(2021, time.June, 1, 22, 0, 0, 0, berlin),
+			endTime:   time.Date(2021, time.June, 2, 2, 0, 0, 0, berlin),
+		},
+		{
+			name: "new-york-within-window",
+			now:  time.Date(2021, time.June, 1, 17, 30, 0, 0, newYork),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 22*time.Hour + 30*time.Minute,
+				TTEnd:   2*time.Hour + 30*time.Minute,
+			},
+			startTime: time.Date(2021, time.June, 1, 22, 0, 0, 0, berlin),
+			endTime:   time.Date(2021, time.June, 2, 2, 0, 0, 0, berlin),
+		},
+		{
+			name: "utc-after-window",
+			now:  time.Date(2021, time.June, 2, 0, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 20 * time.Hour,
+				TTEnd:   0,
+			},
+			startTime: time.Date(2021, time.June, 2, 22, 0, 0, 0, berlin),
+			endTime:   time.Date(2021, time.June, 3, 2, 0, 0, 0, berlin),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.startTime.String(), window.StartTime(c.now).String())
+			require.Equal(t, c.endTime.String(), window.EndTime(c.now).String())
+
+			result := window.WithinWindow(c.now)
+			require.Equal(t, c.result.Within, result.Within)
+			require.Equal(t, c.result.TTStart.String(), result.TTStart.String())
+			require.Equal(t, c.result.TTEnd.String(), result.TTEnd.String())
+		})
+	}
+}
+
+func TestTODWindowDuration(t *testing.T) {
+	cases := []struct {
+		name string
+
+		window TODWindow
+		now    time.Time
+
+		result             WindowResult
+		startTime          time.Time
+		endTime            time.Time
+		followingStartTime time.Time
+	}{
+		{
+			name:   "overnight-within",
+			window: TODWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 22}, Duration: 20 * time.Hour},
+			now:    time.Date(2021, time.June, 2, 23, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 23 * time.Hour,
+				TTEnd:   19 * time.Hour,
+			},
+			startTime:          time.Date(2021, time.June, 2, 22, 0, 0, 0, time.UTC),
+			endTime:            time.Date(2021, time.June, 3, 18, 0, 0, 0, time.UTC),
+			followingStartTime: time.Date(2021, time.June, 3, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "overnight-within-previous-day",
+			window: TODWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 22}, Duration: 20 * time.Hour},
+			now:    time.Date(2021, time.June, 3, 3, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 19 * time.Hour,
+				TTEnd:   15 * time.Hour,
+			},
+			startTime:          time.Date(2021, time.June, 2, 22, 0, 0, 0, time.UTC),
+			endTime:            time.Date(2021, time.June, 3, 18, 0, 0, 0, time.UTC),
+			followingStartTime: time.Date(2021, time.June, 3, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "short-before",
+			window: TODWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 22}, Duration: 4 * time.Hour},
+			now:    time.Date(2021, time.June, 3, 3, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 19 * time.Hour,
+			},
+			startTime:          time.Date(2021, time.June, 3, 22, 0, 0, 0, time.UTC),
+			endTime:            time.Date(2021, time.June, 4, 2, 0, 0, 0, time.UTC),
+			followingStartTime: time.Date(2021, time.June, 4, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "elapsed-across-spring-forward",
+			window: TODWindow{Start: TOD{Hour: 1}, End: TOD{Hour: 1}, Duration: 2 * time.Hour},
+			now:    time.Date(2021, time.March, 14, 3, 30, 0, 0, newYork),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 21*time.Hour + 30*time.Minute,
+				TTEnd:   30 * time.Minute,
+			},
+			startTime:          time.Date(2021, time.March, 14, 1, 0, 0, 0, newYork),
+			endTime:            time.Date(2021, time.March, 14, 4, 0, 0, 0, newYork),
+			followingStartTime: time.Date(2021, time.March, 15, 1, 0, 0, 0, newYork),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.startTime.String(), c.window.StartTime(c.now).String())
+			require.Equal(t, c.endTime.String(), c.window.EndTime(c.now).String())
+			require.Equal(t, c.followingStartTime.String(), c.window.FollowingStartTime(c.now).String())
+
+			result := c.window.WithinWindow(c.now)
+			require.Equal(t, c.result.Within, result.Within)
+			require.Equal(t, c.result.TTStart.String(), result.TTStart.String())
+			require.Equal(t, c.result.TTEnd.String(), result.TTEnd.String())
+		})
+	}
+}
+
+func TestTODWindowSeconds(t *testing.T) {
+	cases := []struct {
+		name string
+
+		window TODWindow
+		now    time.Time
+
+		result             WindowResult
+		startTime          time.Time
+		endTime            time.Time
+		followingStartTime time.Time
+	}{
+		{
+			name: "within",
+			window: TODWindow{
+				Start: TOD{Hour: 10, Second: 30},
+				End:   TOD{Hour: 10, Minute: 1, Second: 15, Nanosecond: 500},
+			},
+			now: time.Date(2021, time.June, 1, 10, 1, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 24*time.Hour - 30*time.Second,
+				TTEnd:   15*time.Second + 500,
+			},
+			startTime:          time.Date(2021, time.June, 1, 10, 0, 30, 0, time.UTC),
+			endTime:            time.Date(2021, time.June, 1, 10, 1, 15, 500, time.UTC),
+			followingStartTime: time.Date(2021, time.June, 2, 10, 0, 30, 0, time.UTC),
+		},
+		{
+			name: "overnight-by-seconds",
+			window: TODWindow{
+				Start: TOD{Hour: 10, Second: 30},
+				End:   TOD{Hour: 10, Second: 10},
+			},
+			now: time.Date(2021, time.June, 1, 10, 0, 5, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 25 * time.Second,
+				TTEnd:   5 * time.Second,
+			},
+			startTime:          time.Date(2021, time.May, 31, 10, 0, 30, 0, time.UTC),
+			endTime:            time.Date(2021, time.June, 1, 10, 0, 10, 0, time.UTC),
+			followingStartTime: time.Date(2021, time.June, 1, 10, 0, 30, 0, time.UTC),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.startTime.String(), c.window.StartTime(c.now).String())
+			require.Equal(t, c.endTime.String(), c.window.EndTime(c.now).String())
+			require.Equal(t, c.followingStartTime.String(), c.window.FollowingStartTime(c.now).String())
+
+			result := c.window.WithinWindow(c.now)
+			require.Equal(t, c.result.Within, result.Within)
+			require.Equal(t, c.result.TTStart.String(), result.TTStart.String())
+			require.Equal(t, c.result.TTEnd.String(), result.TTEnd.String())
+		})
+	}
+}
+
+func TestParseTODWindowFor(t *testing.T) {
+	w, err := ParseTODWindowFor("22:00", "20h")
+	require.NoError(t, err)
+	require.Equal(t, TOD{Hour: 22}, w.Start)
+	require.Equal(t, 20*time.Hour, w.Duration)
+
+	_, err = ParseTODWindowFor("22", "20h")
+	require.Contains(t, err.Error(), "start")
+
+	_, err = ParseTODWindowFor("22:00", "0s")
+	require.EqualError(t, err, "duration: must be positive")
+
+	_, err = ParseTODWindowFor("22:00", "24h")
+	require.EqualError(t, err, "duration: must be less than 24h")
+}
+
+func TestNewTODWindowFor(t *testing.T) {
+	w, err := NewTODWindowFor(TOD{Hour: 22}, 4*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, &TODWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 22}, Duration: 4 * time.Hour}, w)
+
+	_, err = NewTODWindowFor(TOD{Hour: 22}, 26*time.Hour)
+	require.EqualError(t, err, "duration: must be less than 24h")
+}
+
+func TestParseTODWindowInLocation(t *testing.T) {
+	w, err := ParseTODWindowInLocation("02:00", "04:00", "Europe/Berlin")
+	require.NoError(t, err)
+	require.Equal(t, TOD{Hour: 2, Minute: 0}, w.Start)
+	require.Equal(t, TOD{Hour: 4, Minute: 0}, w.End)
+	require.Equal(t, "Europe/Berlin", w.Location.String())
+
+	_, err = ParseTODWindowInLocation("02:00", "04:00", "Not/A_Zone")
+	require.Contains(t, err.Error(), "location")
+
+	_, err = ParseTODWindowInLocation("02:00", "4", "Europe/Berlin")
+	require.Contains(t, err.Error(), "end")
+}
+
+func TestTODWindowOccurrence(t *testing.T) {
+	cases := []struct {
+		name string
+
+		window TODWindow
+		t      time.Time
+
+		next     Interval
+		previous Interval
+	}{
+		{
+			name: "before-window",
+			window: TODWindow{
+				Start: TOD{Hour: 10, Minute: 0},
+				End:   TOD{Hour: 20, Minute: 0},
+			},
+			t: time.Date(2000, time.January, 1, 9, 0, 0, 0, time.UTC),
+
+			next: Interval{
+				Start: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 20, 0, 0, 0, time.UTC),
+			},
+			previous: Interval{
+				Start: time.Date(1999, time.December, 31, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(1999, time.December, 31, 20, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "on-start",
+			window: TODWindow{
+				Start: TOD{Hour: 10, Minute: 0},
+				End:   TOD{Hour: 20, Minute: 0},
+			},
+			t: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+
+			next: Interval{
+				Start: time.Date(2000, time.January, 2, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 2, 20, 0, 0, 0, time.UTC),
+			},
+			previous: Interval{
+				Start: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 20, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "overnight-after-midnight",
+			window: TODWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+			},
+			t: time.Date(2000, time.January, 2, 1, 0, 0, 0, time.UTC),
+
+			next: Interval{
+				Start: time.Date(2000, time.January, 2, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 3, 2, 0, 0, 0, time.UTC),
+			},
+			previous: Interval{
+				Start: time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 2, 2, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "location",
+			window: TODWindow{
+				Start:    TOD{Hour: 2, Minute: 0},
+				End:      TOD{Hour: 4, Minute: 0},
+				Location: berlin,
+			},
+			t: time.Date(2021, time.June, 1, 1, 0, 0, 0, time.UTC),
+
+			next: Interval{
+				Start: time.Date(2021, time.June, 2, 2, 0, 0, 0, berlin),
+				End:   time.Date(2021, time.June, 2, 4, 0, 0, 0, berlin),
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.June, 1, 2, 0, 0, 0, berlin),
+				End:   time.Date(2021, time.June, 1, 4, 0, 0, 0, berlin),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			next, ok := c.window.NextOccurrence(c.t)
+			require.True(t, ok)
+			require.Equal(t, c.next.Start.String(), next.Start.String())
+			require.Equal(t, c.next.End.String(), next.End.String())
+
+			previous, ok := c.window.PreviousOccurrence(c.t)
+			require.True(t, ok)
+			require.Equal(t, c.previous.Start.String(), previous.Start.String())
+			require.Equal(t, c.previous.End.String(), previous.End.String())
+		})
+	}
+}
+
+func TestTODWindowPrevious(t *testing.T) {
+	cases := []struct {
+		name string
+
+		window TODWindow
+		now    time.Time
+
+		previousStartTime time.Time
+		previousEndTime   time.Time
+		tsStart           time.Duration
+		tsEnd             time.Duration
+	}{
+		{
+			name: "before-window",
+			window: TODWindow{
+				Start: TOD{Hour: 10, Minute: 0},
+				End:   TOD{Hour: 20, Minute: 0},
+			},
+			now: time.Date(2000, time.January, 1, 9, 0, 0, 0, time.UTC),
+
+			previousStartTime: time.Date(1999, time.December, 31, 10, 0, 0, 0, time.UTC),
+			previousEndTime:   time.Date(1999, time.December, 31, 20, 0, 0, 0, time.UTC),
+			tsStart:           23 * time.Hour,
+			tsEnd:             13 * time.Hour,
+		},
+		{
+			name: "within-window",
+			window: TODWindow{
+				Start: TOD{Hour: 10, Minute: 0},
+				End:   TOD{Hour: 20, Minute: 0},
+			},
+			now: time.Date(2000, time.January, 1, 11, 0, 0, 0, time.UTC),
+
+			previousStartTime: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+			previousEndTime:   time.Date(1999, time.December, 31, 20, 0, 0, 0, time.UTC),
+			tsStart:           time.Hour,
+			tsEnd:             15 * time.Hour,
+		},
+		{
+			name: "on-end",
+			window: TODWindow{
+				Start: TOD{Hour: 10, Minute: 0},
+				End:   TOD{Hour: 20, Minute: 0},
+			},
+			now: time.Date(2000, time.January, 1, 20, 0, 0, 0, time.UTC),
+
+			previousStartTime: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+			previousEndTime:   time.Date(2000, time.January, 1, 20, 0, 0, 0, time.UTC),
+			tsStart:           10 * time.Hour,
+			tsEnd:             0,
+		},
+		{
+			name: "overnight-after-midnight",
+			window: TODWindow{
+				Start: TOD{Hour: 22, Minute: 0},
+				End:   TOD{Hour: 2, Minute: 0},
+			},
+			now: time.Date(2000, time.January, 2, 1, 0, 0, 0, time.UTC),
+
+			previousStartTime: time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+			previousEndTime:   time.Date(2000, time.January, 1, 2, 0, 0, 0, time.UTC),
+			tsStart:           3 * time.Hour,
+			tsEnd:             23 * time.Hour,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.previousStartTime.String(), c.window.PreviousStartTime(c.now).String())
+			require.Equal(t, c.previousEndTime.String(), c.window.PreviousEndTime(c.now).String())
+
+			result := c.window.WithinWindow(c.now)
+			require.Equal(t, c.tsStart.String(), result.TSStart.String())
+			require.Equal(t, c.tsEnd.String(), result.TSEnd.String())
+		})
+	}
+}
+
+func TestTODWindowJSON(t *testing.T) {
+	cases := []struct {
+		name string
+
+		window TODWindow
+		json   string
+	}{
+		{
+			name:   "end",
+			window: TODWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 2}},
+			json:   `{"start":"22:00","end":"02:00"}`,
+		},
+		{
+			name:   "duration",
+			window: TODWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 22}, Duration: 20 * time.Hour},
+			json:   `{"start":"22:00","duration":"20h0m0s"}`,
+		},
+		{
+			name:   "timezone",
+			window: TODWindow{Start: TOD{Hour: 9, Minute: 30}, End: EndOfDay, Location: berlin},
+			json:   `{"start":"09:30","end":"24:00","timezone":"Europe/Berlin"}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b, err := json.Marshal(c.window)
+			require.NoError(t, err)
+			require.Equal(t, c.json, string(b))
+
+			var w TODWindow
+			require.NoError(t, json.Unmarshal(b, &w))
+			require.Equal(t, c.window, w)
+		})
+	}
+}
+
+func TestTODWindowJSONSadPath(t *testing.T) {
+	cases := []struct {
+		json string
+		err  string
+	}{
+		{
+			json: `{"start":"25:00","end":"02:00"}`,
+			err:  "start: invalid hour: 25",
+		},
+		{
+			json: `{"start":"24:00","end":"02:00"}`,
+			err:  "start: 24:00 is only valid as the end of a window",
+		},
+		{
+			json: `{"start":"22:00"}`,
+			err:  "end: invalid format (expected 12:34 or 12:34:56): ",
+		},
+		{
+			json: `{"start":"22:00","end":"02:00","duration":"4h"}`,
+			err:  "end and duration are mutually exclusive",
+		},
+		{
+			json: `{"start":"22:00","duration":"-4h"}`,
+			err:  "duration: must be positive",
+		},
+		{
+			json: `{"start":"22:00","end":"02:00","timezone":"Nowhere/Special"}`,
+			err:  "timezone: unknown time zone Nowhere/Special",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.json, func(t *testing.T) {
+			var w TODWindow
+			require.EqualError(t, json.Unmarshal([]byte(c.json), &w), c.err)
+		})
+	}
+}
+
+func TestTODWindowMarshalFixedZone(t *testing.T) {
+	w := TODWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 2}, Location: time.FixedZone("X", 3600)}
+
+	_, err := json.Marshal(w)
+	require.Contains(t, err.Error(), "timezone: \"X\" is not an IANA time zone name")
+
+	_, err = w.MarshalYAML()
+	require.EqualError(t, err, "timezone: \"X\" is not an IANA time zone name")
+
+	_, err = w.MarshalText()
+	require.EqualError(t, err, "timezone: \"X\" is not an IANA time zone name")
+
+	for _, loc := range []*time.Location{time.Local, time.FixedZone("", 0)} {
+		_, err = json.Marshal(TODWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 2}, Location: loc})
+		require.Contains(t, err.Error(), "is not an IANA time zone name")
+	}
+}
+
+func TestTODWindowYAML(t *testing.T) {
+	w := TODWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 2}, Location: time.UTC}
+
+	// Round trip through JSON, which has the same shape as YAML.
+	v, err := w.MarshalYAML()
+	require.NoError(t, err)
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	var parsed TODWindow
+	require.NoError(t, parsed.UnmarshalYAML(func(v interface{}) error {
+		return json.Unmarshal(b, v)
+	}))
+	require.Equal(t, w, parsed)
+}
+
+func TestTODWindowText(t *testing.T) {
+	for _, s := range []string{"22:00-02:00", "09:30:15-24:00 Europe/Berlin", "22:00+4h0m0s"} {
+		t.Run(s, func(t *testing.T) {
+			var w TODWindow
+			require.NoError(t, w.UnmarshalText([]byte(s)))
+			text, err := w.MarshalText()
+			require.NoError(t, err)
+			require.Equal(t, s, string(text))
+		})
+	}
+
+	var w TODWindow
+	require.NoError(t, w.UnmarshalText([]byte("22:00-02:00 UTC")))
+	require.Equal(t, TODWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 2}, Location: time.UTC}, w)
+
+	require.EqualError(t, w.UnmarshalText([]byte("25:00-02:00")), "start: invalid hour: 25")
+}
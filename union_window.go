@@ -0,0 +1,161 @@
+package timewindow
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Union returns a window that is open whenever any of the given windows is
+// open.
+func Union(windows ...Window) *UnionWindow {
+	return &UnionWindow{Windows: windows}
+}
+
+// UnionWindow is open whenever any of its windows is open. Occurrences of its
+// windows that overlap or touch are merged into a single occurrence, so the
+// end of an occurrence is the end of a continuously open period.
+//
+// Continuously open periods are followed for at most a year in either
+// direction from the time that is passed in.
+type UnionWindow struct {
+	Windows []Window
+}
+
+var _ Window = &UnionWindow{}
+
+// String returns the windows separated by semicolons. If all of the windows
+// are TODWindows or TODWeekWindows, this is the form accepted by ParseWindow.
+func (u *UnionWindow) String() string {
+	specs := make([]string, len(u.Windows))
+	for i, w := range u.Windows {
+		specs[i] = fmt.Sprint(w)
+	}
+	return strings.Join(specs, "; ")
+}
+
+// WithinWindow returns true if within any of the windows. It also returns the
+// time until the next window.
+func (u *UnionWindow) WithinWindow(now time.Time) WindowResult {
+	return withinOccurrences(u, now)
+}
+
+// NextOccurrence returns the first merged window that starts after t. It
+// returns false if t falls within a period that is still open at the end of
+// the horizon, since the next merged window cannot be told apart from it.
+func (u *UnionWindow) NextOccurrence(t time.Time) (Interval, bool) {
+	after := t
+	if cur, ok := u.cover(t); ok {
+		if cur.End.Sub(t) > horizon {
+			return Interval{}, false
+		}
+		after = cur.End
+	}
+	start, ok := u.nextStart(after)
+	if !ok {
+		return Interval{}, false
+	}
+	o, ok := u.cover(start)
+	if !ok || !o.Start.After(t) {
+		return Interval{}, false
+	}
+	return o, true
+}
+
+// PreviousOccurrence returns the last merged window that starts at or before t.
+func (u *UnionWindow) PreviousOccurrence(t time.Time) (Interval, bool) {
+	if cur, ok := u.cover(t); ok {
+		return cur, true
+	}
+
+	var (
+		latest Interval
+		found  bool
+	)
+	for _, w := range u.Windows {
+		o, ok := previousNonEmptyOccurrence(w, t)
+		if ok && (!found || o.End.After(latest.End)) {
+			latest, found = o, true
+		}
+	}
+	if !found {
+		return Interval{}, false
+	}
+	return u.cover(latest.Start)
+}
+
+// Occurrences returns the merged windows that overlap the range from from
+// (inclusive) to to (exclusive).
+func (u *UnionWindow) Occurrences(from, to time.Time) []Interval {
+	return occurrences(u, from, to)
+}
+
+// Iterate returns an iterator over the merged windows, beginning with the first
+// one that ends after from.
+func (u *UnionWindow) Iterate(from time.Time) *OccurrenceIterator {
+	return newOccurrenceIterator(u, from)
+}
+
+// cover returns the continuously open period that t falls within.
+func (u *UnionWindow) cover(t time.Time) (Interval, bool) {
+	var (
+		cur   Interval
+		found bool
+	)
+	for _, w := range u.Windows {
+		o, ok := w.PreviousOccurrence(t)
+		if !ok || !o.Contains(t) {
+			continue
+		}
+		if !found {
+			cur, found = o, true
+			continue
+		}
+		if o.Start.Before(cur.Start) {
+			cur.Start = o.Start
+		}
+		if o.End.After(cur.End) {
+			cur.End = o.End
+		}
+	}
+	if !found {
+		return Interval{}, false
+	}
+
+	// Extend the period by occurrences that overlap or touch it.
+	for changed := true; changed && cur.End.Sub(t) <= horizon; {
+		changed = false
+		for _, w := range u.Windows {
+			if o, ok := w.PreviousOccurrence(cur.End); ok && o.End.After(cur.End) {
+				cur.End = o.End
+				changed = true
+			}
+		}
+	}
+	for changed := true; changed && t.Sub(cur.Start) <= horizon; {
+		changed = false
+		for _, w := range u.Windows {
+			if o, ok := w.PreviousOccurrence(cur.Start.Add(-1)); ok && !o.End.Before(cur.Start) {
+				cur.Start = o.Start
+				changed = true
+			}
+		}
+	}
+
+	return cur, true
+}
+
+// nextStart returns the earliest start of a window that starts after t.
+func (u *UnionWindow) nextStart(t time.Time) (time.Time, bool) {
+	var (
+		next  time.Time
+		found bool
+	)
+	for _, w := range u.Windows {
+		o, ok := nextNonEmptyOccurrence(w, t)
+		if ok && (!found || o.Start.Before(next)) {
+			next, found = o.Start, true
+		}
+	}
+	return next, found
+}
@@ -0,0 +1,275 @@
+package timewindow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnionWindow(t *testing.T) {
+	cases := []struct {
+		name string
+
+		window *UnionWindow
+		now    time.Time
+
+		result   WindowResult
+		previous Interval
+		next     Interval
+	}{
+		{
+			name: "within-weekly",
+			window: Union(
+				&TODWeekWindow{Start: TOD{Hour: 2}, End: TOD{Hour: 4}, Weekdays: Weekdays{time.Tuesday: true}},
+				&TODWeekWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 16}, Weekdays: Weekdays{time.Saturday: true}},
+			),
+			now: time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 62 * time.Hour,
+				TTEnd:   4 * time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 16, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2000, time.January, 4, 2, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 4, 4, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "between-weekly",
+			window: Union(
+				&TODWeekWindow{Start: TOD{Hour: 2}, End: TOD{Hour: 4}, Weekdays: Weekdays{time.Tuesday: true}},
+				&TODWeekWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 16}, Weekdays: Weekdays{time.Saturday: true}},
+			),
+			now: time.Date(2000, time.January, 2, 12, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 38 * time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 16, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2000, time.January, 4, 2, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 4, 4, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "overlapping",
+			window: Union(
+				&TODWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 14}},
+				&TODWindow{Start: TOD{Hour: 12}, End: TOD{Hour: 18}},
+			),
+			now: time.Date(2000, time.January, 1, 11, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 23 * time.Hour,
+				TTEnd:   7 * time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 18, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2000, time.January, 2, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 2, 18, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "adjacent-on-start",
+			window: Union(
+				&TODWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 12}},
+				&TODWindow{Start: TOD{Hour: 12}, End: TOD{Hour: 14}},
+			),
+			now: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 0,
+				TTEnd:   4 * time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 14, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2000, time.January, 2, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 2, 14, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "adjacent-on-boundary",
+			window: Union(
+				&TODWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 12}},
+				&TODWindow{Start: TOD{Hour: 12}, End: TOD{Hour: 14}},
+			),
+			now: time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 22 * time.Hour,
+				TTEnd:   2 * time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 14, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2000, time.January, 2, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 2, 14, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "overnight",
+			window: Union(
+				&TODWindow{Start: TOD{Hour: 22}, End: TOD{Hour: 2}},
+				&TODWindow{Start: TOD{Hour: 1}, End: TOD{Hour: 3}},
+			),
+			now: time.Date(2000, time.January, 2, 1, 30, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 20*time.Hour + 30*time.Minute,
+				TTEnd:   time.Hour + 30*time.Minute,
+			},
+			previous: Interval{
+				Start: time.Date(2000, time.January, 1, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 2, 3, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2000, time.January, 2, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 3, 3, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "gap",
+			window: Union(
+				&TODWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 12}},
+				&TODWindow{Start: TOD{Hour: 13}, End: TOD{Hour: 14}},
+			),
+			now: time.Date(2000, time.January, 1, 12, 30, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 30 * time.Minute,
+			},
+			previous: Interval{
+				Start: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2000, time.January, 1, 13, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, time.January, 1, 14, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result := c.window.WithinWindow(c.now)
+			require.Equal(t, c.result.Within, result.Within)
+			require.Equal(t, c.result.TTStart.String(), result.TTStart.String())
+			require.Equal(t, c.result.TTEnd.String(), result.TTEnd.String())
+
+			previous, ok := c.window.PreviousOccurrence(c.now)
+			require.True(t, ok)
+			require.Equal(t, c.previous.Start.String(), previous.Start.String())
+			require.Equal(t, c.previous.End.String(), previous.End.String())
+
+			next, ok := c.window.NextOccurrence(c.now)
+			require.True(t, ok)
+			require.Equal(t, c.next.Start.String(), next.Start.String())
+			require.Equal(t, c.next.End.String(), next.End.String())
+		})
+	}
+}
+
+func TestUnionWindowEmpty(t *testing.T) {
+	now := time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	for name, window := range map[string]*UnionWindow{
+		"no-windows":       Union(),
+		"no-weekdays":      Union(&TODWeekWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 12}, Weekdays: Weekdays{}}),
+		"zero-length-only": Union(&TODWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 10}}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			result := window.WithinWindow(now)
+			require.False(t, result.Within)
+			require.Equal(t, Never, result.TTStart)
+			require.Equal(t, Never, result.TTWithinChange())
+
+			_, ok := window.NextOccurrence(now)
+			require.False(t, ok)
+			_, ok = window.PreviousOccurrence(now)
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestUnionWindowAlwaysOpen(t *testing.T) {
+	window := Union(
+		&TODWindow{Start: TOD{Hour: 0}, End: TOD{Hour: 12}},
+		&TODWindow{Start: TOD{Hour: 12}, End: TOD{Hour: 0}},
+	)
+
+	result := window.WithinWindow(time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC))
+	require.True(t, result.Within)
+	require.True(t, result.TTEnd > horizon)
+}
+
+func TestUnionWindowAlwaysOpenOccurrences(t *testing.T) {
+	weekdays, err := ParseTODWeekWindow("00:00", "24:00", []string{"mon", "tue", "wed", "thu", "fri"})
+	require.NoError(t, err)
+	weekends, err := ParseTODWeekWindow("00:00", "24:00", []string{"sat", "sun"})
+	require.NoError(t, err)
+	window := Union(weekdays, weekends)
+	now := time.Date(2021, time.June, 7, 11, 0, 0, 0, time.UTC)
+
+	occurrences := window.Occurrences(now, now.Add(72*time.Hour))
+	require.Len(t, occurrences, 1)
+	require.False(t, occurrences[0].Start.After(now))
+	require.True(t, occurrences[0].End.After(now.Add(72*time.Hour)))
+
+	_, ok := window.NextOccurrence(now)
+	require.False(t, ok)
+
+	result := window.WithinWindow(now)
+	require.True(t, result.Within)
+	require.Equal(t, Never, result.TTStart)
+	require.True(t, result.TTEnd > horizon)
+}
+
+func TestUnionWindowOccurrences(t *testing.T) {
+	window := Union(
+		&TODWindow{Start: TOD{Hour: 10}, End: TOD{Hour: 14}},
+		&TODWindow{Start: TOD{Hour: 12}, End: TOD{Hour: 18}},
+		&TODWeekWindow{Start: TOD{Hour: 18}, End: TOD{Hour: 20}, Weekdays: Weekdays{time.Sunday: true}},
+	)
+
+	occurrences := window.Occurrences(
+		time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2000, time.January, 3, 12, 0, 0, 0, time.UTC),
+	)
+	require.Equal(t, []Interval{
+		{
+			Start: time.Date(2000, time.January, 1, 10, 0, 0, 0, time.UTC),
+			End:   time.Date(2000, time.January, 1, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			Start: time.Date(2000, time.January, 2, 10, 0, 0, 0, time.UTC),
+			End:   time.Date(2000, time.January, 2, 20, 0, 0, 0, time.UTC),
+		},
+		{
+			Start: time.Date(2000, time.January, 3, 10, 0, 0, 0, time.UTC),
+			End:   time.Date(2000, time.January, 3, 18, 0, 0, 0, time.UTC),
+		},
+	}, occurrences)
+}
@@ -0,0 +1,31 @@
+package timewindow_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nstogner/timewindow"
+)
+
+func ExampleUnionWindow() {
+	tuesday, err := timewindow.ParseTODWeekWindow("02:00", "04:00", []string{"Tue"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	saturday, err := timewindow.ParseTODWeekWindow("10:00", "16:00", []string{"Sat"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	window := timewindow.Union(tuesday, saturday)
+
+	now := time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC)
+	result := window.WithinWindow(now)
+	fmt.Println("within: ", result.Within)
+	fmt.Println("untilEnd: ", result.TTEnd)
+	fmt.Println("untilStart: ", result.TTStart)
+	// Output:
+	// within:  true
+	// untilEnd:  4h0m0s
+	// untilStart:  62h0m0s
+}
@@ -1,9 +1,138 @@
 package timewindow
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 // UntilTomorrow returns the amount of time until midnight.
 func UntilTomorrow(now time.Time) time.Duration {
-	tomorrow := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).Add(24 * time.Hour)
+	tomorrow := wallClock(date(now).AddDate(0, 0, 1), TOD{}, now.Location())
 	return tomorrow.Sub(now)
 }
+
+// date returns the calendar date of t as midnight UTC. Calendar-day arithmetic
+// is done on these values (using AddDate) so that it is not affected by DST
+// transitions in t's location.
+func date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// wallClock returns the instant at which clocks in loc show tod on the calendar
+// date of day. EndOfDay resolves to midnight at the start of the next day.
+//
+// A time of day that is skipped by a DST transition resolves as if the
+// transition had not happened yet, which moves it forward by the length of the
+// gap (02:30 becomes 03:30 when clocks spring forward from 02:00 to 03:00). A
+// time of day that is repeated resolves to its first occurrence.
+func wallClock(day time.Time, tod TOD, loc *time.Location) time.Time {
+	if d := tod.SinceMidnight(); d >= 24*time.Hour {
+		// The end of the day is midnight of the following day.
+		day, tod = day.AddDate(0, 0, 1), TOD{}.Add(d-24*time.Hour)
+	}
+
+	naive := time.Date(day.Year(), day.Month(), day.Day(), tod.Hour, tod.Minute, tod.Second, tod.Nanosecond, time.UTC)
+
+	_, before := naive.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := naive.Add(24 * time.Hour).In(loc).Zone()
+
+	withBefore := naive.Add(-time.Duration(before) * time.Second).In(loc)
+	if date(withBefore).Equal(date(naive)) && withBefore.Hour() == tod.Hour && withBefore.Minute() == tod.Minute {
+		return withBefore
+	}
+	withAfter := naive.Add(-time.Duration(after) * time.Second).In(loc)
+	if date(withAfter).Equal(date(naive)) && withAfter.Hour() == tod.Hour && withAfter.Minute() == tod.Minute {
+		return withAfter
+	}
+
+	// The time of day does not exist on this day.
+	return withBefore
+}
+
+// windowLocation returns the location that a window with the given Location
+// is resolved in: loc if set and otherwise the location of now.
+func windowLocation(loc *time.Location, now time.Time) *time.Location {
+	if loc != nil {
+		return loc
+	}
+	return now.Location()
+}
+
+// nextDayOccurrence returns the first occurrence that starts after t, looking
+// at up to maxDays calendar days in loc. The occurrence function returns the
+// occurrence that starts on a day, or false if there is none.
+func nextDayOccurrence(t time.Time, loc *time.Location, maxDays int, occurrence func(day time.Time) (Interval, bool)) (Interval, bool) {
+	day := date(t.In(loc)).AddDate(0, 0, -1)
+	for i := 0; i <= maxDays+1; i++ {
+		if o, ok := occurrence(day); ok && o.Start.After(t) {
+			return o, true
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return Interval{}, false
+}
+
+// previousDayOccurrence returns the last occurrence that starts at or before t,
+// looking at up to maxDays calendar days in loc. The occurrence function
+// returns the occurrence that starts on a day, or false if there is none.
+func previousDayOccurrence(t time.Time, loc *time.Location, maxDays int, occurrence func(day time.Time) (Interval, bool)) (Interval, bool) {
+	day := date(t.In(loc)).AddDate(0, 0, 1)
+	for i := 0; i <= maxDays+1; i++ {
+		if o, ok := occurrence(day); ok && !o.Start.After(t) {
+			return o, true
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+	return Interval{}, false
+}
+
+// parseWindowDuration parses the length of a window, which must be positive.
+func parseWindowDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, errors.New("must be positive")
+	}
+	return d, nil
+}
+
+// checkDailyDuration returns an error if d is not a valid length of a daily
+// window. The windows of consecutive days would overlap and never close if d
+// were a day or longer, so such windows must be weekly windows.
+func checkDailyDuration(d time.Duration) error {
+	if d >= 24*time.Hour {
+		return errors.New("must be less than 24h")
+	}
+	return nil
+}
+
+// timezoneName returns the name of loc, or "" if loc is nil. It returns an
+// error if the name cannot be loaded by parseTimezone, as is the case for
+// locations created with time.FixedZone, and for time.Local, whose meaning
+// depends on the host.
+func timezoneName(loc *time.Location) (string, error) {
+	if loc == nil {
+		return "", nil
+	}
+	name := loc.String()
+	if _, err := time.LoadLocation(name); err != nil || loc == time.Local || name == "Local" || name == "" {
+		return "", fmt.Errorf("timezone: %q is not an IANA time zone name", name)
+	}
+	return name, nil
+}
+
+// parseTimezone loads the location with the given IANA name. It returns nil if
+// name is empty.
+func parseTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("timezone: %w", err)
+	}
+	return loc, nil
+}
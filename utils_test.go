@@ -3,6 +3,7 @@ package timewindow
 import (
 	"testing"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/stretchr/testify/require"
 )
@@ -36,3 +37,123 @@ func TestUntilTomorrow(t *testing.T) {
 		})
 	}
 }
+
+func TestUntilTomorrowDST(t *testing.T) {
+	cases := []struct {
+		name  string
+		now   time.Time
+		until time.Duration
+	}{
+		{
+			name:  "spring-forward",
+			now:   time.Date(2021, time.March, 14, 0, 0, 0, 0, newYork),
+			until: 23 * time.Hour,
+		},
+		{
+			name:  "fall-back",
+			now:   time.Date(2021, time.November, 7, 0, 0, 0, 0, newYork),
+			until: 25 * time.Hour,
+		},
+		{
+			name:  "skipped-midnight",
+			now:   time.Date(2018, time.November, 3, 12, 0, 0, 0, saoPaulo),
+			until: 12 * time.Hour,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.until, UntilTomorrow(c.now))
+		})
+	}
+}
+
+func TestWallClock(t *testing.T) {
+	cases := []struct {
+		name string
+		day  time.Time
+		tod  TOD
+		loc  *time.Location
+		want time.Time
+	}{
+		{
+			name: "regular",
+			day:  time.Date(2021, time.March, 13, 0, 0, 0, 0, time.UTC),
+			tod:  TOD{Hour: 2, Minute: 30},
+			loc:  newYork,
+			want: time.Date(2021, time.March, 13, 7, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "skipped-moves-forward",
+			day:  time.Date(2021, time.March, 14, 0, 0, 0, 0, time.UTC),
+			tod:  TOD{Hour: 2, Minute: 30},
+			loc:  newYork,
+			want: time.Date(2021, time.March, 14, 7, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "after-spring-forward",
+			day:  time.Date(2021, time.March, 14, 0, 0, 0, 0, time.UTC),
+			tod:  TOD{Hour: 3, Minute: 30},
+			loc:  newYork,
+			want: time.Date(2021, time.March, 14, 7, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "end-of-day",
+			day:  time.Date(2021, time.March, 13, 0, 0, 0, 0, time.UTC),
+			tod:  EndOfDay,
+			loc:  newYork,
+			want: time.Date(2021, time.March, 14, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "repeated-resolves-to-first",
+			day:  time.Date(2021, time.November, 7, 0, 0, 0, 0, time.UTC),
+			tod:  TOD{Hour: 1, Minute: 30},
+			loc:  newYork,
+			want: time.Date(2021, time.November, 7, 5, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "after-fall-back",
+			day:  time.Date(2021, time.November, 7, 0, 0, 0, 0, time.UTC),
+			tod:  TOD{Hour: 2, Minute: 30},
+			loc:  newYork,
+			want: time.Date(2021, time.November, 7, 7, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "skipped-midnight",
+			day:  time.Date(2018, time.November, 4, 0, 0, 0, 0, time.UTC),
+			tod:  TOD{Hour: 0, Minute: 0},
+			loc:  saoPaulo,
+			want: time.Date(2018, time.November, 4, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "southern-hemisphere-repeated",
+			day:  time.Date(2021, time.April, 4, 0, 0, 0, 0, time.UTC),
+			tod:  TOD{Hour: 2, Minute: 30},
+			loc:  sydney,
+			want: time.Date(2021, time.April, 3, 15, 30, 0, 0, time.UTC),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := wallClock(c.day, c.tod, c.loc)
+			require.True(t, c.want.Equal(got), "want %v, got %v", c.want, got)
+			require.Equal(t, c.loc, got.Location())
+		})
+	}
+}
+
+var (
+	newYork  = mustLoadLocation("America/New_York")
+	saoPaulo = mustLoadLocation("America/Sao_Paulo")
+	sydney   = mustLoadLocation("Australia/Sydney")
+	berlin   = mustLoadLocation("Europe/Berlin")
+)
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
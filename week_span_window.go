@@ -0,0 +1,227 @@
+package timewindow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseWeekSpanWindow parses a weekly window such as "Fri 18:00 - Mon 06:00".
+func ParseWeekSpanWindow(s string) (*WeekSpanWindow, error) {
+	invalidErr := errors.New("invalid format (expected Fri 18:00 - Mon 06:00): " + s)
+
+	split := strings.SplitN(s, "-", 2)
+	if len(split) != 2 {
+		return nil, invalidErr
+	}
+
+	startDay, start, err := parseWeekdayTOD(split[0])
+	if err == nil && start == EndOfDay {
+		err = errEndOfDayStart
+	}
+	if err != nil {
+		return nil, fmt.Errorf("start: %w", err)
+	}
+	endDay, end, err := parseWeekdayTOD(split[1])
+	if err != nil {
+		return nil, fmt.Errorf("end: %w", err)
+	}
+
+	return &WeekSpanWindow{StartDay: startDay, Start: start, EndDay: endDay, End: end}, nil
+}
+
+// ParseWeekSpanWindowInLocation is like ParseWeekSpanWindow but resolves the
+// window in the location with the given IANA name (for example
+// "Europe/Berlin").
+func ParseWeekSpanWindowInLocation(s, location string) (*WeekSpanWindow, error) {
+	w, err := ParseWeekSpanWindow(s)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(location)
+	if err != nil {
+		return nil, fmt.Errorf("location: %w", err)
+	}
+	w.Location = loc
+
+	return w, nil
+}
+
+// parseWeekdayTOD parses a weekday followed by a time of day, such as
+// "Fri 18:00".
+func parseWeekdayTOD(s string) (time.Weekday, TOD, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return 0, TOD{}, errors.New("invalid format (expected Fri 18:00): " + strings.TrimSpace(s))
+	}
+
+	wd, ok := strToWeekday[strings.ToLower(fields[0])]
+	if !ok {
+		return 0, TOD{}, fmt.Errorf("unrecognized weekday: %s", fields[0])
+	}
+
+	tod, err := ParseTOD(fields[1])
+	if err != nil {
+		return 0, TOD{}, err
+	}
+
+	return wd, tod, nil
+}
+
+// formatWeekdayTOD formats a weekday and a time of day in the form accepted by
+// parseWeekdayTOD.
+func formatWeekdayTOD(wd time.Weekday, tod TOD) string {
+	return wd.String()[:3] + " " + tod.String()
+}
+
+// weekSpanWindowJSON is the JSON and YAML representation of a WeekSpanWindow.
+type weekSpanWindowJSON struct {
+	Start    string `json:"start" yaml:"start"`
+	End      string `json:"end" yaml:"end"`
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
+
+func newWeekSpanWindowJSON(w WeekSpanWindow) (weekSpanWindowJSON, error) {
+	tz, err := timezoneName(w.Location)
+	if err != nil {
+		return weekSpanWindowJSON{}, err
+	}
+	return weekSpanWindowJSON{Start: formatWeekdayTOD(w.StartDay, w.Start), End: formatWeekdayTOD(w.EndDay, w.End), Timezone: tz}, nil
+}
+
+// window parses v with the same validation as ParseWeekSpanWindow.
+func (v weekSpanWindowJSON) window() (*WeekSpanWindow, error) {
+	w, err := ParseWeekSpanWindow(v.Start + " - " + v.End)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Location, err = parseTimezone(v.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// MarshalJSON implements json.Marshaler. The window is written as an object
+// such as {"start":"Fri 18:00","end":"Mon 06:00","timezone":"UTC"}.
+func (w WeekSpanWindow) MarshalJSON() ([]byte, error) {
+	v, err := newWeekSpanWindowJSON(w)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The fields are validated like
+// the arguments of ParseWeekSpanWindow.
+func (w *WeekSpanWindow) UnmarshalJSON(b []byte) error {
+	var v weekSpanWindowJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	return w.set(v)
+}
+
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2 and
+// gopkg.in/yaml.v3.
+func (w WeekSpanWindow) MarshalYAML() (interface{}, error) {
+	return newWeekSpanWindowJSON(w)
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2,
+// which gopkg.in/yaml.v3 also supports.
+func (w *WeekSpanWindow) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v weekSpanWindowJSON
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	return w.set(v)
+}
+
+// set sets w to the window parsed from v.
+func (w *WeekSpanWindow) set(v weekSpanWindowJSON) error {
+	parsed, err := v.window()
+	if err != nil {
+		return err
+	}
+	*w = *parsed
+	return nil
+}
+
+// WeekSpanWindow is a weekly window that starts at Start on StartDay and ends
+// at End on the following EndDay, such as Friday 18:00 to Monday 06:00. A
+// window that ends at the same weekday and time of day that it starts at lasts
+// for the whole week.
+type WeekSpanWindow struct {
+	StartDay time.Weekday
+	Start    TOD
+	EndDay   time.Weekday
+	End      TOD
+
+	// Location is the time zone that the window is expressed in. If nil, the
+	// window is resolved in the location of the time that is passed in.
+	Location *time.Location
+}
+
+var _ Window = &WeekSpanWindow{}
+
+// WithinWindow returns true if within the window. It also returns the time until
+// the next window.
+func (w *WeekSpanWindow) WithinWindow(now time.Time) WindowResult {
+	return withinOccurrences(w, now)
+}
+
+// NextOccurrence returns the first window that starts after t.
+func (w *WeekSpanWindow) NextOccurrence(t time.Time) (Interval, bool) {
+	loc := windowLocation(w.Location, t)
+	return nextDayOccurrence(t, loc, 7, func(day time.Time) (Interval, bool) {
+		return w.occurrence(day, loc)
+	})
+}
+
+// PreviousOccurrence returns the last window that starts at or before t.
+func (w *WeekSpanWindow) PreviousOccurrence(t time.Time) (Interval, bool) {
+	loc := windowLocation(w.Location, t)
+	return previousDayOccurrence(t, loc, 7, func(day time.Time) (Interval, bool) {
+		return w.occurrence(day, loc)
+	})
+}
+
+// Occurrences returns the windows that overlap the range from from (inclusive)
+// to to (exclusive).
+func (w *WeekSpanWindow) Occurrences(from, to time.Time) []Interval {
+	return occurrences(w, from, to)
+}
+
+// Iterate returns an iterator over the windows, beginning with the first one
+// that ends after from.
+func (w *WeekSpanWindow) Iterate(from time.Time) *OccurrenceIterator {
+	return newOccurrenceIterator(w, from)
+}
+
+// occurrence returns the window that starts on day, if day is StartDay.
+func (w *WeekSpanWindow) occurrence(day time.Time, loc *time.Location) (Interval, bool) {
+	if day.Weekday() != w.StartDay {
+		return Interval{}, false
+	}
+
+	start := wallClock(day, w.Start, loc)
+	end := wallClock(day.AddDate(0, 0, w.spanDays()), w.End, loc)
+	if end.Before(start) {
+		end = start
+	}
+	return Interval{Start: start, End: end}, true
+}
+
+// spanDays returns the number of days from StartDay to EndDay.
+func (w *WeekSpanWindow) spanDays() int {
+	days := (int(w.EndDay) - int(w.StartDay) + 7) % 7
+	if days == 0 && !w.End.After(w.Start) {
+		days = 7
+	}
+	return days
+}
@@ -0,0 +1,246 @@
+package timewindow
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeekSpanWindow(t *testing.T) {
+	weekend := WeekSpanWindow{
+		StartDay: time.Friday,
+		Start:    TOD{Hour: 18},
+		EndDay:   time.Monday,
+		End:      TOD{Hour: 6},
+	}
+
+	cases := []struct {
+		name string
+
+		window WeekSpanWindow
+		now    time.Time
+
+		result   WindowResult
+		previous Interval
+		next     Interval
+	}{
+		{
+			name:   "before",
+			window: weekend,
+			now:    time.Date(2021, time.June, 4, 17, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.May, 28, 18, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.May, 31, 6, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.June, 4, 18, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 7, 6, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "within",
+			window: weekend,
+			now:    time.Date(2021, time.June, 6, 12, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 5*24*time.Hour + 6*time.Hour,
+				TTEnd:   18 * time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.June, 4, 18, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 7, 6, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.June, 11, 18, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 14, 6, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "on-end",
+			window: weekend,
+			now:    time.Date(2021, time.June, 7, 6, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  false,
+				TTStart: 4*24*time.Hour + 12*time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.June, 4, 18, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 7, 6, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.June, 11, 18, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 14, 6, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "same-day",
+			window: WeekSpanWindow{
+				StartDay: time.Tuesday,
+				Start:    TOD{Hour: 2},
+				EndDay:   time.Tuesday,
+				End:      TOD{Hour: 4},
+			},
+			now: time.Date(2021, time.June, 8, 3, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 7*24*time.Hour - time.Hour,
+				TTEnd:   time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.June, 8, 2, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 8, 4, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.June, 15, 2, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 15, 4, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "whole-week",
+			window: WeekSpanWindow{
+				StartDay: time.Monday,
+				EndDay:   time.Monday,
+			},
+			now: time.Date(2021, time.June, 9, 12, 0, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 4*24*time.Hour + 12*time.Hour,
+				TTEnd:   4*24*time.Hour + 12*time.Hour,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.June, 7, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 14, 0, 0, 0, 0, time.UTC),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.June, 14, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, time.June, 21, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "location",
+			window: WeekSpanWindow{
+				StartDay: time.Friday,
+				Start:    TOD{Hour: 18},
+				EndDay:   time.Monday,
+				End:      TOD{Hour: 6},
+				Location: berlin,
+			},
+			now: time.Date(2021, time.June, 4, 16, 30, 0, 0, time.UTC),
+
+			result: WindowResult{
+				Within:  true,
+				TTStart: 7*24*time.Hour - 30*time.Minute,
+				TTEnd:   2*24*time.Hour + 11*time.Hour + 30*time.Minute,
+			},
+			previous: Interval{
+				Start: time.Date(2021, time.June, 4, 18, 0, 0, 0, berlin),
+				End:   time.Date(2021, time.June, 7, 6, 0, 0, 0, berlin),
+			},
+			next: Interval{
+				Start: time.Date(2021, time.June, 11, 18, 0, 0, 0, berlin),
+				End:   time.Date(2021, time.June, 14, 6, 0, 0, 0, berlin),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result := c.window.WithinWindow(c.now)
+			require.Equal(t, c.result.Within, result.Within)
+			require.Equal(t, c.result.TTStart.String(), result.TTStart.String())
+			require.Equal(t, c.result.TTEnd.String(), result.TTEnd.String())
+
+			previous, ok := c.window.PreviousOccurrence(c.now)
+			require.True(t, ok)
+			require.Equal(t, c.previous.Start.String(), previous.Start.String())
+			require.Equal(t, c.previous.End.String(), previous.End.String())
+
+			next, ok := c.window.NextOccurrence(c.now)
+			require.True(t, ok)
+			require.Equal(t, c.next.Start.String(), next.Start.String())
+			require.Equal(t, c.next.End.String(), next.End.String())
+		})
+	}
+}
+
+func TestParseWeekSpanWindow(t *testing.T) {
+	w, err := ParseWeekSpanWindow("Fri 18:00 - Mon 06:00")
+	require.NoError(t, err)
+	require.Equal(t, &WeekSpanWindow{StartDay: time.Friday, Start: TOD{Hour: 18}, EndDay: time.Monday, End: TOD{Hour: 6}}, w)
+
+	w, err = ParseWeekSpanWindow("saturday 22:30-sunday 04:00")
+	require.NoError(t, err)
+	require.Equal(t, &WeekSpanWindow{StartDay: time.Saturday, Start: TOD{Hour: 22, Minute: 30}, EndDay: time.Sunday, End: TOD{Hour: 4}}, w)
+
+	_, err = ParseWeekSpanWindow("Fri 18:00")
+	require.EqualError(t, err, "invalid format (expected Fri 18:00 - Mon 06:00): Fri 18:00")
+
+	_, err = ParseWeekSpanWindow("Fri - Mon 06:00")
+	require.EqualError(t, err, "start: invalid format (expected Fri 18:00): Fri")
+
+	_, err = ParseWeekSpanWindow("Fri 18:00 - Mun 06:00")
+	require.EqualError(t, err, "end: unrecognized weekday: Mun")
+
+	w, err = ParseWeekSpanWindow("Fri 18:00 - Sun 24:00")
+	require.NoError(t, err)
+	require.Equal(t, EndOfDay, w.End)
+
+	_, err = ParseWeekSpanWindow("Fri 24:00 - Sun 24:00")
+	require.EqualError(t, err, "start: 24:00 is only valid as the end of a window")
+
+	_, err = ParseWeekSpanWindow("Fri 25:00 - Mon 06:00")
+	require.EqualError(t, err, "start: invalid hour: 25")
+
+	w, err = ParseWeekSpanWindowInLocation("Fri 18:00 - Mon 06:00", "Europe/Berlin")
+	require.NoError(t, err)
+	require.Equal(t, "Europe/Berlin", w.Location.String())
+
+	_, err = ParseWeekSpanWindowInLocation("Fri 18:00 - Mon 06:00", "Not/A_Zone")
+	require.Contains(t, err.Error(), "location")
+}
+
+func TestWeekSpanWindowJSON(t *testing.T) {
+	w := WeekSpanWindow{StartDay: time.Friday, Start: TOD{Hour: 18}, EndDay: time.Monday, End: TOD{Hour: 6}, Location: berlin}
+
+	b, err := json.Marshal(w)
+	require.NoError(t, err)
+	require.Equal(t, `{"start":"Fri 18:00","end":"Mon 06:00","timezone":"Europe/Berlin"}`, string(b))
+
+	var parsed WeekSpanWindow
+	require.NoError(t, json.Unmarshal(b, &parsed))
+	require.Equal(t, w, parsed)
+}
+
+func TestWeekSpanWindowJSONSadPath(t *testing.T) {
+	cases := []struct {
+		json string
+		err  string
+	}{
+		{
+			json: `{"start":"Fri 18:00"}`,
+			err:  "end: invalid format (expected Fri 18:00): ",
+		},
+		{
+			json: `{"start":"Fri 18:00","end":"Mon 06:00","timezone":"Nowhere/Special"}`,
+			err:  "timezone: unknown time zone Nowhere/Special",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.json, func(t *testing.T) {
+			var w WeekSpanWindow
+			require.EqualError(t, json.Unmarshal([]byte(c.json), &w), c.err)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package timewindow_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nstogner/timewindow"
+)
+
+func ExampleWeekSpanWindow() {
+	window, err := timewindow.ParseWeekSpanWindow("Fri 18:00 - Mon 06:00")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	now := time.Date(2021, time.June, 6, 12, 0, 0, 0, time.UTC)
+	result := window.WithinWindow(now)
+	fmt.Println("Within:", result.Within)
+	fmt.Println("Time until end:", result.TTEnd)
+	// Output:
+	// Within: true
+	// Time until end: 18h0m0s
+}
@@ -0,0 +1,214 @@
+package timewindow
+
+import (
+	"encoding/json"
+	"math/bits"
+	"strings"
+	"time"
+)
+
+// WeekdaySet is a set of weekdays. Bit i is set if time.Weekday(i) is in the
+// set. The zero value is the empty set.
+//
+// Unlike Weekdays, a WeekdaySet does not allocate and can be compared with ==.
+type WeekdaySet uint8
+
+// AllWeekdays is the set of all seven weekdays.
+const AllWeekdays WeekdaySet = 1<<7 - 1
+
+// NewWeekdaySet returns the set of the given weekdays.
+func NewWeekdaySet(days ...time.Weekday) WeekdaySet {
+	var s WeekdaySet
+	for _, wd := range days {
+		s = s.Add(wd)
+	}
+	return s
+}
+
+// ParseWeekdaySet is like ParseWeekdays but returns a WeekdaySet.
+func ParseWeekdaySet(daysOfWeek []string) (WeekdaySet, error) {
+	return strToWeekday.ParseWeekdaySet(daysOfWeek)
+}
+
+// Contains reports whether wd is in s.
+func (s WeekdaySet) Contains(wd time.Weekday) bool {
+	return validWeekday(wd) && s&(1<<uint(wd)) != 0
+}
+
+// Add returns s with wd added. Values that are not weekdays are ignored.
+func (s WeekdaySet) Add(wd time.Weekday) WeekdaySet {
+	if !validWeekday(wd) {
+		return s
+	}
+	return s | 1<<uint(wd)
+}
+
+// Remove returns s with wd removed.
+func (s WeekdaySet) Remove(wd time.Weekday) WeekdaySet {
+	if !validWeekday(wd) {
+		return s
+	}
+	return s &^ (1 << uint(wd))
+}
+
+// Union returns the weekdays that are in s or t.
+func (s WeekdaySet) Union(t WeekdaySet) WeekdaySet {
+	return s | t
+}
+
+// Intersect returns the weekdays that are in both s and t.
+func (s WeekdaySet) Intersect(t WeekdaySet) WeekdaySet {
+	return s & t
+}
+
+// Complement returns the weekdays that are not in s.
+func (s WeekdaySet) Complement() WeekdaySet {
+	return ^s & AllWeekdays
+}
+
+// Len returns the number of weekdays in s.
+func (s WeekdaySet) Len() int {
+	return bits.OnesCount8(uint8(s & AllWeekdays))
+}
+
+// Days returns the weekdays in s in order from Sunday to Saturday.
+func (s WeekdaySet) Days() []time.Weekday {
+	days := make([]time.Weekday, 0, s.Len())
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		if s.Contains(wd) {
+			days = append(days, wd)
+		}
+	}
+	return days
+}
+
+// Map returns s as a Weekdays map.
+func (s WeekdaySet) Map() Weekdays {
+	w := make(Weekdays, s.Len())
+	for _, wd := range s.Days() {
+		w[wd] = true
+	}
+	return w
+}
+
+// String returns the weekdays in order from Monday to Sunday, such as
+// "Mon,Wed" or "Sat,Sun". Three or more consecutive weekdays are written as a
+// range such as "Mon-Fri". The result is accepted by ParseWeekdaySet after
+// splitting it at commas.
+func (s WeekdaySet) String() string {
+	var items []string
+	for i := 1; i <= 7; {
+		if !s.Contains(time.Weekday(i % 7)) {
+			i++
+			continue
+		}
+
+		j := i
+		for j < 7 && s.Contains(time.Weekday((j+1)%7)) {
+			j++
+		}
+
+		from, to := time.Weekday(i % 7).String()[:3], time.Weekday(j % 7).String()[:3]
+		switch j - i {
+		case 0:
+			items = append(items, from)
+		case 1:
+			items = append(items, from, to)
+		default:
+			items = append(items, from+"-"+to)
+		}
+		i = j + 1
+	}
+	return strings.Join(items, ",")
+}
+
+// NextDayOfWeek returns the next weekday in s after today. It returns today if
+// today is the only weekday in s and -1 if s is empty.
+func (s WeekdaySet) NextDayOfWeek(today time.Weekday) time.Weekday {
+	if s&AllWeekdays == 0 {
+		return -1
+	}
+	return (today + time.Weekday(s.DaysUntilNextDayOfWeek(today))) % 7
+}
+
+// DaysUntilNextDayOfWeek returns the number of days from today until the next
+// weekday in s, from 1 to 7. It returns 0 if s is empty, in which case there is
+// no next weekday.
+func (s WeekdaySet) DaysUntilNextDayOfWeek(today time.Weekday) int {
+	for days := 1; days <= 7; days++ {
+		if s.Contains((today + time.Weekday(days)) % 7) {
+			return days
+		}
+	}
+	return 0
+}
+
+// names returns the abbreviated names of the weekdays, such as "mon", in order
+// from Monday to Sunday.
+func (s WeekdaySet) names() []string {
+	names := []string{}
+	for i := 1; i <= 7; i++ {
+		if wd := time.Weekday(i % 7); s.Contains(wd) {
+			names = append(names, strings.ToLower(wd.String()[:3]))
+		}
+	}
+	return names
+}
+
+// MarshalText implements encoding.TextMarshaler. The weekdays are written as a
+// comma separated list such as "mon,fri".
+func (s WeekdaySet) MarshalText() ([]byte, error) {
+	return []byte(strings.Join(s.names(), ",")), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It parses a comma
+// separated list of weekdays using ParseWeekdaySet.
+func (s *WeekdaySet) UnmarshalText(text []byte) error {
+	var days []string
+	if len(text) > 0 {
+		days = strings.Split(string(text), ",")
+	}
+	return s.fromNames(days)
+}
+
+// MarshalJSON implements json.Marshaler. The weekdays are written as a list
+// such as ["mon","fri"].
+func (s WeekdaySet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.names())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It parses a list of weekdays
+// using ParseWeekdaySet.
+func (s *WeekdaySet) UnmarshalJSON(b []byte) error {
+	var days []string
+	if err := json.Unmarshal(b, &days); err != nil {
+		return err
+	}
+	return s.fromNames(days)
+}
+
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2 and
+// gopkg.in/yaml.v3.
+func (s WeekdaySet) MarshalYAML() (interface{}, error) {
+	return s.names(), nil
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2,
+// which gopkg.in/yaml.v3 also supports.
+func (s *WeekdaySet) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var days []string
+	if err := unmarshal(&days); err != nil {
+		return err
+	}
+	return s.fromNames(days)
+}
+
+// fromNames sets s to the parsed weekdays.
+func (s *WeekdaySet) fromNames(days []string) error {
+	parsed, err := ParseWeekdaySet(days)
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
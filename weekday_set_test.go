@@ -0,0 +1,100 @@
+package timewindow
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeekdaySet(t *testing.T) {
+	s := NewWeekdaySet(time.Monday, time.Wednesday)
+
+	require.True(t, s.Contains(time.Monday))
+	require.False(t, s.Contains(time.Tuesday))
+	require.False(t, s.Contains(time.Weekday(7)))
+	require.Equal(t, 2, s.Len())
+
+	s = s.Add(time.Friday).Add(time.Weekday(9)).Remove(time.Monday)
+	require.Equal(t, []time.Weekday{time.Wednesday, time.Friday}, s.Days())
+
+	weekend := NewWeekdaySet(time.Saturday, time.Sunday)
+	require.Equal(t, NewWeekdaySet(time.Wednesday, time.Friday, time.Saturday, time.Sunday), s.Union(weekend))
+	require.Equal(t, NewWeekdaySet(time.Friday), s.Intersect(NewWeekdaySet(time.Thursday, time.Friday)))
+	require.Equal(t, NewWeekdaySet(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday), weekend.Complement())
+	require.Equal(t, WeekdaySet(0), AllWeekdays.Complement())
+	require.Equal(t, 7, AllWeekdays.Len())
+	require.Equal(t, []time.Weekday{}, WeekdaySet(0).Days())
+}
+
+func TestWeekdaySetString(t *testing.T) {
+	cases := []struct {
+		set WeekdaySet
+		s   string
+	}{
+		{set: 0, s: ""},
+		{set: NewWeekdaySet(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday), s: "Mon-Fri"},
+		{set: NewWeekdaySet(time.Saturday, time.Sunday), s: "Sat,Sun"},
+		{set: NewWeekdaySet(time.Monday, time.Wednesday, time.Thursday, time.Friday, time.Sunday), s: "Mon,Wed-Fri,Sun"},
+		{set: AllWeekdays, s: "Mon-Sun"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			require.Equal(t, c.s, c.set.String())
+			if c.s == "" {
+				return
+			}
+			parsed, err := ParseWeekdaySet(strings.Split(c.s, ","))
+			require.NoError(t, err)
+			require.Equal(t, c.set, parsed)
+		})
+	}
+}
+
+func TestWeekdaySetNextDayOfWeek(t *testing.T) {
+	s := NewWeekdaySet(time.Monday, time.Wednesday, time.Thursday)
+
+	require.Equal(t, time.Wednesday, s.NextDayOfWeek(time.Tuesday))
+	require.Equal(t, 1, s.DaysUntilNextDayOfWeek(time.Tuesday))
+	require.Equal(t, time.Monday, s.NextDayOfWeek(time.Friday))
+	require.Equal(t, 3, s.DaysUntilNextDayOfWeek(time.Friday))
+
+	single := NewWeekdaySet(time.Tuesday)
+	require.Equal(t, time.Tuesday, single.NextDayOfWeek(time.Tuesday))
+	require.Equal(t, 7, single.DaysUntilNextDayOfWeek(time.Tuesday))
+
+	require.Equal(t, time.Weekday(-1), WeekdaySet(0).NextDayOfWeek(time.Tuesday))
+	require.Equal(t, 0, WeekdaySet(0).DaysUntilNextDayOfWeek(time.Tuesday))
+}
+
+func TestWeekdaySetMap(t *testing.T) {
+	w := Weekdays{time.Monday: true, time.Friday: true, time.Sunday: false}
+
+	s := w.Set()
+	require.Equal(t, NewWeekdaySet(time.Monday, time.Friday), s)
+	require.Equal(t, Weekdays{time.Monday: true, time.Friday: true}, s.Map())
+}
+
+func TestWeekdaySetMarshaling(t *testing.T) {
+	s := NewWeekdaySet(time.Friday, time.Monday)
+
+	text, err := s.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "mon,fri", string(text))
+
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.Equal(t, `["mon","fri"]`, string(b))
+
+	var parsed WeekdaySet
+	require.NoError(t, json.Unmarshal([]byte(`["weekends","mon-tue"]`), &parsed))
+	require.Equal(t, NewWeekdaySet(time.Saturday, time.Sunday, time.Monday, time.Tuesday), parsed)
+
+	require.NoError(t, parsed.UnmarshalText([]byte("wed")))
+	require.Equal(t, NewWeekdaySet(time.Wednesday), parsed)
+
+	require.EqualError(t, json.Unmarshal([]byte(`["funday"]`), &parsed), "unrecognized weekday: funday")
+}
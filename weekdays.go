@@ -2,12 +2,11 @@ package timewindow
 
 import (
 	"fmt"
-	"sort"
 	"strings"
 	"time"
 )
 
-var strToWeekday = map[string]time.Weekday{
+var strToWeekday = WeekdayNames{
 	"sunday": time.Sunday,
 	"sun":    time.Sunday,
 	"su":     time.Sunday,
@@ -40,64 +39,183 @@ var strToWeekday = map[string]time.Weekday{
 	"sa":       time.Saturday,
 }
 
+// weekdayGroups are the named groups of weekdays accepted by ParseWeekdays.
+var weekdayGroups = map[string]WeekdaySet{
+	"weekdays": NewWeekdaySet(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday),
+	"weekends": NewWeekdaySet(time.Saturday, time.Sunday),
+	"daily":    AllWeekdays,
+	"*":        AllWeekdays,
+	"none":     0,
+}
+
+// ParseWeekdays parses English weekday names such as "monday", "mon" or "mo".
+// See WeekdayNames.ParseWeekdays for the accepted ranges and groups.
 func ParseWeekdays(daysOfWeek []string) (Weekdays, error) {
-	wds := make(Weekdays)
+	return strToWeekday.ParseWeekdays(daysOfWeek)
+}
+
+// WeekdayNames maps lower case weekday names to weekdays. It can be used to
+// parse weekday names in other languages:
+//
+//	german := timewindow.WeekdayNames{"montag": time.Monday, "mo": time.Monday, ...}
+//	wds, err := german.ParseWeekdays([]string{"mo-fr"})
+type WeekdayNames map[string]time.Weekday
+
+// DefaultWeekdayNames returns a copy of the English weekday names that are
+// accepted by ParseWeekdays. It can be extended with names in other languages.
+func DefaultWeekdayNames() WeekdayNames {
+	names := make(WeekdayNames, len(strToWeekday))
+	for name, wd := range strToWeekday {
+		names[name] = wd
+	}
+	return names
+}
+
+// ParseWeekdays parses weekdays. Each element is either a weekday name, a range
+// of weekdays such as "mon-fri" or a group. A range that ends on an earlier
+// weekday than it starts wraps around the end of the week, so "fri-mon" is
+// Friday to Monday. The groups are "weekdays" (Monday to Friday), "weekends",
+// "daily" or "*" (every day) and "none". Case and surrounding space are
+// ignored.
+func (n WeekdayNames) ParseWeekdays(daysOfWeek []string) (Weekdays, error) {
+	s, err := n.ParseWeekdaySet(daysOfWeek)
+	if err != nil {
+		return nil, err
+	}
+	return s.Map(), nil
+}
+
+// ParseWeekdaySet is like ParseWeekdays but returns a WeekdaySet.
+func (n WeekdayNames) ParseWeekdaySet(daysOfWeek []string) (WeekdaySet, error) {
+	var s WeekdaySet
 	for _, d := range daysOfWeek {
-		wd, ok := strToWeekday[strings.TrimSpace(strings.ToLower(d))]
+		v := strings.TrimSpace(strings.ToLower(d))
+
+		if group, ok := weekdayGroups[v]; ok {
+			s = s.Union(group)
+			continue
+		}
+
+		if i := strings.Index(v, "-"); i >= 0 {
+			fromName, toName := strings.TrimSpace(v[:i]), strings.TrimSpace(v[i+1:])
+			from, ok := n[fromName]
+			if !ok {
+				return 0, fmt.Errorf("unrecognized weekday in range %s: %s", v, fromName)
+			}
+			if !validWeekday(from) {
+				return 0, fmt.Errorf("invalid weekday in range %s: %s maps to %d", v, fromName, from)
+			}
+			to, ok := n[toName]
+			if !ok {
+				return 0, fmt.Errorf("unrecognized weekday in range %s: %s", v, toName)
+			}
+			if !validWeekday(to) {
+				return 0, fmt.Errorf("invalid weekday in range %s: %s maps to %d", v, toName, to)
+			}
+			for wd := from; ; wd = (wd + 1) % 7 {
+				s = s.Add(wd)
+				if wd == to {
+					break
+				}
+			}
+			continue
+		}
+
+		wd, ok := n[v]
 		if !ok {
-			return nil, fmt.Errorf("unrecognized weekday: %s", d)
+			return 0, fmt.Errorf("unrecognized weekday: %s", d)
 		}
-		wds[wd] = true
+		if !validWeekday(wd) {
+			return 0, fmt.Errorf("invalid weekday: %s maps to %d", v, wd)
+		}
+		s = s.Add(wd)
 	}
-	return wds, nil
+	return s, nil
+}
+
+// validWeekday reports whether wd is one of time.Sunday to time.Saturday.
+func validWeekday(wd time.Weekday) bool {
+	return wd >= time.Sunday && wd <= time.Saturday
 }
 
+// Weekdays is a set of weekdays as a map. Weekdays that map to false are not in
+// the set. WeekdaySet is the more compact form that the window types use;
+// Set converts between the two.
 type Weekdays map[time.Weekday]bool
 
-// NextDayOfWeek returns the next matching day of the week.
-// It will return the current day if the current day matches AND
-// there are no other matching days.
-func (w Weekdays) NextDayOfWeek(today time.Weekday) time.Weekday {
-	if len(w) == 0 {
-		return -1
+// Set returns the weekdays as a WeekdaySet.
+func (w Weekdays) Set() WeekdaySet {
+	var s WeekdaySet
+	for wd, ok := range w {
+		if ok {
+			s = s.Add(wd)
+		}
 	}
+	return s
+}
+
+// MarshalText implements encoding.TextMarshaler. The weekdays are written as a
+// comma separated list such as "mon,fri".
+func (w Weekdays) MarshalText() ([]byte, error) {
+	return w.Set().MarshalText()
+}
 
-	var days []int
-	for day := range w {
-		days = append(days, int(day))
+// UnmarshalText implements encoding.TextUnmarshaler. It parses a comma
+// separated list of weekdays using ParseWeekdays.
+func (w *Weekdays) UnmarshalText(text []byte) error {
+	var s WeekdaySet
+	if err := s.UnmarshalText(text); err != nil {
+		return err
 	}
-	sort.Ints(days)
+	*w = s.Map()
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The weekdays are written as a list
+// such as ["mon","fri"].
+func (w Weekdays) MarshalJSON() ([]byte, error) {
+	return w.Set().MarshalJSON()
+}
 
-	// Wrap around.
-	// 2 5 (6) --> 2
-	if int(today) > days[len(days)-1] {
-		return time.Weekday(days[0])
+// UnmarshalJSON implements json.Unmarshaler. It parses a list of weekdays
+// using ParseWeekdays.
+func (w *Weekdays) UnmarshalJSON(b []byte) error {
+	var s WeekdaySet
+	if err := s.UnmarshalJSON(b); err != nil {
+		return err
 	}
+	*w = s.Map()
+	return nil
+}
 
-	// (1) 2 5 --> 2
-	// 2 (3) 5 --> 5
-	for _, day := range days {
-		if day > int(today) {
-			return time.Weekday(day)
-		}
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2 and
+// gopkg.in/yaml.v3.
+func (w Weekdays) MarshalYAML() (interface{}, error) {
+	return w.Set().MarshalYAML()
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2,
+// which gopkg.in/yaml.v3 also supports.
+func (w *Weekdays) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s WeekdaySet
+	if err := s.UnmarshalYAML(unmarshal); err != nil {
+		return err
 	}
+	*w = s.Map()
+	return nil
+}
 
-	return today
+// NextDayOfWeek returns the next matching day of the week.
+// It will return the current day if the current day matches AND
+// there are no other matching days. Weekdays that map to false do not match.
+// It returns -1 if no weekdays match.
+func (w Weekdays) NextDayOfWeek(today time.Weekday) time.Weekday {
+	return w.Set().NextDayOfWeek(today)
 }
 
 // DaysUntilNextDayOfWeek calculates the next day of the week that matches
-// and returns the number of days until then.
+// and returns the number of days until then. Weekdays that map to false do
+// not match. It returns 0 if no weekdays match.
 func (w Weekdays) DaysUntilNextDayOfWeek(today time.Weekday) int {
-	if len(w) == 0 {
-		return 0
-	}
-
-	next := w.NextDayOfWeek(today)
-	if next > today {
-		return int(next - today)
-	} else if next < today {
-		return (7 - int(today)) + int(next)
-	} else {
-		return 7
-	}
+	return w.Set().DaysUntilNextDayOfWeek(today)
 }
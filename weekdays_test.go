@@ -1,6 +1,7 @@
 package timewindow
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 	"time"
@@ -77,6 +78,26 @@ func TestWeekdays(t *testing.T) {
 			nextDayOfWeek:          time.Tuesday,
 			daysUntilNextDayOfWeek: 7,
 		},
+		{
+			name: "false-entries",
+			weekdays: Weekdays(map[time.Weekday]bool{
+				time.Monday:    true,
+				time.Wednesday: false,
+				time.Saturday:  false,
+			}),
+			now:                    time.Tuesday,
+			nextDayOfWeek:          time.Monday,
+			daysUntilNextDayOfWeek: 6,
+		},
+		{
+			name: "only-false-entries",
+			weekdays: Weekdays(map[time.Weekday]bool{
+				time.Wednesday: false,
+			}),
+			now:                    time.Tuesday,
+			nextDayOfWeek:          time.Weekday(-1),
+			daysUntilNextDayOfWeek: 0,
+		},
 	}
 
 	for _, c := range cases {
@@ -104,6 +125,38 @@ func TestParseWeekdaysHappyPath(t *testing.T) {
 			s: []string{"mon", "Tues", "Thurs"},
 			w: Weekdays{time.Monday: true, time.Tuesday: true, time.Thursday: true},
 		},
+		{
+			s: []string{"mon-wed", "Sat"},
+			w: Weekdays{time.Monday: true, time.Tuesday: true, time.Wednesday: true, time.Saturday: true},
+		},
+		{
+			s: []string{"Fri - mon"},
+			w: Weekdays{time.Friday: true, time.Saturday: true, time.Sunday: true, time.Monday: true},
+		},
+		{
+			s: []string{"thu-thu"},
+			w: Weekdays{time.Thursday: true},
+		},
+		{
+			s: []string{"weekdays"},
+			w: Weekdays{time.Monday: true, time.Tuesday: true, time.Wednesday: true, time.Thursday: true, time.Friday: true},
+		},
+		{
+			s: []string{"Weekends", "wed"},
+			w: Weekdays{time.Saturday: true, time.Sunday: true, time.Wednesday: true},
+		},
+		{
+			s: []string{"daily"},
+			w: Weekdays{time.Monday: true, time.Tuesday: true, time.Wednesday: true, time.Thursday: true, time.Friday: true, time.Saturday: true, time.Sunday: true},
+		},
+		{
+			s: []string{"none"},
+			w: Weekdays{},
+		},
+		{
+			s: []string{"*"},
+			w: Weekdays{time.Monday: true, time.Tuesday: true, time.Wednesday: true, time.Thursday: true, time.Friday: true, time.Saturday: true, time.Sunday: true},
+		},
 	}
 
 	for _, c := range cases {
@@ -131,6 +184,31 @@ func TestParseWeekdaysSadPath(t *testing.T) {
 			s:       []string{"not-a-day"},
 			errText: "not-a-day",
 		},
+		{
+			name:    "bad-range-start",
+			s:       []string{"funday-fri"},
+			errText: "unrecognized weekday in range funday-fri: funday",
+		},
+		{
+			name:    "bad-range-end",
+			s:       []string{"mon-"},
+			errText: "unrecognized weekday in range mon-: ",
+		},
+		{
+			name:    "padded-range",
+			s:       []string{"   Foo - fri "},
+			errText: "unrecognized weekday in range foo - fri: foo",
+		},
+		{
+			name:    "non-ascii-range",
+			s:       []string{"ȺȺȺȺ-x"},
+			errText: "unrecognized weekday in range ⱥⱥⱥⱥ-x: ⱥⱥⱥⱥ",
+		},
+		{
+			name:    "invalid-utf8-range",
+			s:       []string{"\xff\xff\xff\xff-x"},
+			errText: "unrecognized weekday in range",
+		},
 	}
 
 	for _, c := range cases {
@@ -140,3 +218,70 @@ func TestParseWeekdaysSadPath(t *testing.T) {
 		})
 	}
 }
+
+func TestWeekdaysMarshaling(t *testing.T) {
+	wds := Weekdays{time.Friday: true, time.Monday: true, time.Sunday: false}
+
+	text, err := wds.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "mon,fri", string(text))
+
+	b, err := json.Marshal(wds)
+	require.NoError(t, err)
+	require.Equal(t, `["mon","fri"]`, string(b))
+
+	var parsed Weekdays
+	require.NoError(t, parsed.UnmarshalText([]byte("Mon, fri")))
+	require.Equal(t, Weekdays{time.Monday: true, time.Friday: true}, parsed)
+
+	require.NoError(t, json.Unmarshal([]byte(`["sat","sun"]`), &parsed))
+	require.Equal(t, Weekdays{time.Saturday: true, time.Sunday: true}, parsed)
+
+	require.NoError(t, parsed.UnmarshalText(nil))
+	require.Equal(t, Weekdays{}, parsed)
+
+	require.EqualError(t, json.Unmarshal([]byte(`["mon","funday"]`), &parsed), "unrecognized weekday: funday")
+	require.EqualError(t, parsed.UnmarshalText([]byte("mon,funday")), "unrecognized weekday: funday")
+}
+
+func TestWeekdayNames(t *testing.T) {
+	german := WeekdayNames{
+		"mo": time.Monday,
+		"di": time.Tuesday,
+		"mi": time.Wednesday,
+		"do": time.Thursday,
+		"fr": time.Friday,
+		"sa": time.Saturday,
+		"so": time.Sunday,
+	}
+
+	w, err := german.ParseWeekdays([]string{"Fr-Mo", "mi"})
+	require.NoError(t, err)
+	require.Equal(t, Weekdays{time.Friday: true, time.Saturday: true, time.Sunday: true, time.Monday: true, time.Wednesday: true}, w)
+
+	_, err = german.ParseWeekdays([]string{"tue"})
+	require.EqualError(t, err, "unrecognized weekday: tue")
+
+	names := DefaultWeekdayNames()
+	names["dienstag"] = time.Tuesday
+	w, err = names.ParseWeekdays([]string{"dienstag", "thu"})
+	require.NoError(t, err)
+	require.Equal(t, Weekdays{time.Tuesday: true, time.Thursday: true}, w)
+
+	// The default names are not changed.
+	_, err = ParseWeekdays([]string{"dienstag"})
+	require.EqualError(t, err, "unrecognized weekday: dienstag")
+}
+
+func TestWeekdayNamesInvalidWeekday(t *testing.T) {
+	names := WeekdayNames{"mon": time.Monday, "son": 7, "neg": -1}
+
+	_, err := names.ParseWeekdays([]string{"mon-son"})
+	require.EqualError(t, err, "invalid weekday in range mon-son: son maps to 7")
+
+	_, err = names.ParseWeekdays([]string{"neg-mon"})
+	require.EqualError(t, err, "invalid weekday in range neg-mon: neg maps to -1")
+
+	_, err = names.ParseWeekdaySet([]string{"son"})
+	require.EqualError(t, err, "invalid weekday: son maps to 7")
+}
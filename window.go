@@ -1,6 +1,50 @@
 package timewindow
 
-import "time"
+import (
+	"math"
+	"time"
+)
+
+// Never is reported as the time until a window starts when it does not start
+// again, and as the time since a window started or ended when it has not
+// started or ended before.
+const Never = time.Duration(math.MaxInt64)
+
+// Window is implemented by all window types.
+type Window interface {
+	// WithinWindow returns true if now is within the window. It also returns
+	// the time until the next window.
+	WithinWindow(now time.Time) WindowResult
+
+	// NextOccurrence returns the first occurrence of the window that starts
+	// after t. It returns false if the window does not occur after t.
+	NextOccurrence(t time.Time) (Interval, bool)
+
+	// PreviousOccurrence returns the last occurrence of the window that starts
+	// at or before t. It returns false if the window does not occur before t.
+	PreviousOccurrence(t time.Time) (Interval, bool)
+
+	// Occurrences returns the occurrences of the window that overlap the range
+	// from from (inclusive) to to (exclusive), in order of their start time.
+	// Occurrences of zero length are left out.
+	Occurrences(from, to time.Time) []Interval
+
+	// Iterate returns an iterator over the occurrences of the window,
+	// beginning with the first one that ends after from.
+	Iterate(from time.Time) *OccurrenceIterator
+}
+
+// Interval is a concrete occurrence of a window. Start is inclusive and End is
+// exclusive.
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains returns true if t is within the interval.
+func (i Interval) Contains(t time.Time) bool {
+	return !t.Before(i.Start) && t.Before(i.End)
+}
 
 // WithinWindow returns true if within a window. It also returns the time until the next
 // window starts.
@@ -68,6 +112,13 @@ type WindowResult struct {
 	Within  bool
 	TTStart time.Duration
 	TTEnd   time.Duration
+
+	// TSStart is the Time Since the most recent window started. It includes
+	// the window that now is within. It is Never if no window has started.
+	TSStart time.Duration
+	// TSEnd is the Time Since the most recent window ended. It is Never if no
+	// window has ended.
+	TSEnd time.Duration
 }
 
 // TTWithinChange is the Time Til there is a change in the .Within window result.